@@ -0,0 +1,151 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+// Format identifies a compressed-stream format recognized by DetectFormat.
+type Format int
+
+const (
+	// FormatUnknown means none of the recognized magic headers matched.
+	FormatUnknown Format = iota
+	// FormatMinLZStream is a framed MinLZ stream (see stream.go).
+	FormatMinLZStream
+	// FormatMinLZBlock is a single raw MinLZ block (see Encode/Decode).
+	FormatMinLZBlock
+	// FormatSnappyStream is a framed Snappy or S2 stream; both share the
+	// "sNaPpY" stream identifier at the framing level.
+	FormatSnappyStream
+	// FormatGzip is a gzip stream.
+	FormatGzip
+	// FormatZstd is a zstd stream.
+	FormatZstd
+)
+
+var (
+	minLZStreamMagic  = frameChunk(chunkStreamID, []byte(streamMagicBody))
+	snappyStreamMagic = frameChunk(snappyChunkStreamID, []byte(snappyMagicBody))
+	gzipMagic         = []byte{0x1f, 0x8b, 0x08}
+	zstdMagic         = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// autoDetectPeekLen is the number of leading bytes DetectFormat needs to see
+// to recognize any of the formats above.
+const autoDetectPeekLen = 10
+
+// DetectFormat reports which compressed-stream format b's leading bytes
+// match, and how many of those bytes make up the matched magic header. It
+// returns FormatUnknown, 0 if b doesn't start with a recognized header and
+// doesn't look like a raw MinLZ block either.
+func DetectFormat(b []byte) (Format, int) {
+	switch {
+	case bytes.HasPrefix(b, minLZStreamMagic):
+		return FormatMinLZStream, len(minLZStreamMagic)
+	case bytes.HasPrefix(b, snappyStreamMagic):
+		return FormatSnappyStream, len(snappyStreamMagic)
+	case bytes.HasPrefix(b, gzipMagic):
+		return FormatGzip, len(gzipMagic)
+	case bytes.HasPrefix(b, zstdMagic):
+		return FormatZstd, len(zstdMagic)
+	case len(b) > 0 && b[0] == 0:
+		// Raw MinLZ blocks have no magic of their own; a MinLZ marker byte
+		// of 0 is the best signal available short of decoding it outright.
+		return FormatMinLZBlock, 1
+	default:
+		return FormatUnknown, 0
+	}
+}
+
+// NewAutoReader returns an io.ReadCloser that sniffs the first few bytes of
+// r to detect its format (see DetectFormat) and transparently decodes it,
+// so callers handling payloads of unknown provenance (uploads, backups)
+// don't have to hard-code which decompressor to construct. Detection is
+// deferred to the first Read call; a format that can't be recognized, or
+// that fails to initialize (e.g. a corrupt gzip header), surfaces as an
+// error from that first Read.
+func NewAutoReader(r io.Reader) io.ReadCloser {
+	return &autoReader{br: bufio.NewReaderSize(r, autoDetectPeekLen)}
+}
+
+type autoReader struct {
+	br  *bufio.Reader
+	rc  io.ReadCloser
+	err error
+}
+
+func (a *autoReader) init() {
+	if a.rc != nil || a.err != nil {
+		return
+	}
+	peek, _ := a.br.Peek(autoDetectPeekLen)
+	switch format, _ := DetectFormat(peek); format {
+	case FormatMinLZStream:
+		a.rc = io.NopCloser(NewReader(a.br))
+	case FormatSnappyStream:
+		a.rc = io.NopCloser(NewSnappyReader(a.br))
+	case FormatGzip:
+		gz, err := gzip.NewReader(a.br)
+		if err != nil {
+			a.err = err
+			return
+		}
+		a.rc = gz
+	case FormatZstd:
+		zr, err := newZstdReader(a.br)
+		if err != nil {
+			a.err = err
+			return
+		}
+		a.rc = zr
+	case FormatMinLZBlock:
+		raw, err := io.ReadAll(a.br)
+		if err != nil {
+			a.err = err
+			return
+		}
+		decoded, err := Decode(nil, raw)
+		if err != nil {
+			a.err = err
+			return
+		}
+		a.rc = io.NopCloser(bytes.NewReader(decoded))
+	default:
+		a.err = fmt.Errorf("minlz: NewAutoReader: unrecognized stream format")
+	}
+}
+
+// Read implements io.Reader, detecting the format on the first call.
+func (a *autoReader) Read(p []byte) (int, error) {
+	a.init()
+	if a.err != nil {
+		return 0, a.err
+	}
+	return a.rc.Read(p)
+}
+
+// Close implements io.Closer.
+func (a *autoReader) Close() error {
+	if a.rc != nil {
+		return a.rc.Close()
+	}
+	return nil
+}
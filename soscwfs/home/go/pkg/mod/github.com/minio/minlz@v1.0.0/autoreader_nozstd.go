@@ -0,0 +1,29 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !minlz_zstd
+
+package minlz
+
+import (
+	"errors"
+	"io"
+)
+
+// newZstdReader is the default stub used when the minlz_zstd build tag is
+// not set, so NewAutoReader doesn't pull in klauspost/compress/zstd unless
+// a caller opts in.
+func newZstdReader(io.Reader) (io.ReadCloser, error) {
+	return nil, errors.New("minlz: zstd support not built in; rebuild with -tags minlz_zstd")
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+)
+
+func TestNewAutoReaderMinLZStream(t *testing.T) {
+	data := []byte("hello from a framed minlz stream")
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	got, err := io.ReadAll(NewAutoReader(bytes.NewReader(buf.Bytes())))
+	if err != nil {
+		t.Fatalf("AutoReader: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("got %q, want %q", got, data)
+	}
+}
+
+func TestNewAutoReaderSnappyStream(t *testing.T) {
+	data := []byte("hello from a framed snappy stream")
+	var buf bytes.Buffer
+	w := NewSnappyWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	got, err := io.ReadAll(NewAutoReader(bytes.NewReader(buf.Bytes())))
+	if err != nil {
+		t.Fatalf("AutoReader: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("got %q, want %q", got, data)
+	}
+}
+
+func TestNewAutoReaderGzip(t *testing.T) {
+	data := []byte("hello from a gzip stream")
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	if _, err := gw.Write(data); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	got, err := io.ReadAll(NewAutoReader(bytes.NewReader(buf.Bytes())))
+	if err != nil {
+		t.Fatalf("AutoReader: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("got %q, want %q", got, data)
+	}
+}
+
+func TestNewAutoReaderRawBlock(t *testing.T) {
+	data := []byte("hello from a single raw minlz block")
+	enc, err := Encode(nil, data, LevelFastest)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	got, err := io.ReadAll(NewAutoReader(bytes.NewReader(enc)))
+	if err != nil {
+		t.Fatalf("AutoReader: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("got %q, want %q", got, data)
+	}
+}
+
+func TestNewAutoReaderUnknown(t *testing.T) {
+	if _, err := io.ReadAll(NewAutoReader(bytes.NewReader([]byte("not a recognized format")))); err == nil {
+		t.Fatal("want error for unrecognized format, got nil")
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	format, n := DetectFormat([]byte{0x1f, 0x8b, 0x08, 0x00})
+	if format != FormatGzip || n != 3 {
+		t.Fatalf("got (%v, %d), want (FormatGzip, 3)", format, n)
+	}
+	format, n = DetectFormat([]byte{0x28, 0xb5, 0x2f, 0xfd})
+	if format != FormatZstd || n != 4 {
+		t.Fatalf("got (%v, %d), want (FormatZstd, 4)", format, n)
+	}
+	format, _ = DetectFormat(nil)
+	if format != FormatUnknown {
+		t.Fatalf("got %v, want FormatUnknown", format)
+	}
+}
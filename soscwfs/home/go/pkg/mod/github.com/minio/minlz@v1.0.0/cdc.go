@@ -0,0 +1,109 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+import "fmt"
+
+// cdcWindow is the size of the sliding window the content-defined chunker
+// hashes over.
+const cdcWindow = 48
+
+// cdcTable holds the in/out contribution tables for the rolling hash used by
+// the content-defined chunker. They are fixed, well-mixed 64-bit constants
+// indexed by byte value (a Rabin-Karp/buzhash style table).
+var cdcTable = func() (t [256]uint64) {
+	// A simple splitmix64-derived table; any well-distributed table works,
+	// since only the boundary statistics (not specific values) matter.
+	x := uint64(0x9e3779b97f4a7c15)
+	for i := range t {
+		x += 0x9e3779b97f4a7c15
+		z := x
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		z = z ^ (z >> 31)
+		t[i] = z
+	}
+	return t
+}()
+
+// cdcChunker finds content-defined chunk boundaries using a buzhash-style
+// rolling hash over a 48-byte sliding window, so that inserting or removing
+// bytes early in a stream only invalidates a bounded number of downstream
+// chunks (unlike fixed-size splitting).
+type cdcChunker struct {
+	min, avg, max int
+	mask          uint64
+
+	window [cdcWindow]byte
+	wpos   int
+	filled int
+	hash   uint64
+	n      int // bytes accumulated in the chunk so far
+}
+
+// newCDCChunker validates min <= avg <= max and returns a chunker that cuts
+// chunks around avg bytes, never smaller than min nor larger than max.
+func newCDCChunker(min, avg, max int) (*cdcChunker, error) {
+	if min <= 0 || avg <= 0 || max <= 0 || !(min <= avg && avg <= max) {
+		return nil, fmt.Errorf("minlz: invalid CDC bounds (min=%d avg=%d max=%d)", min, avg, max)
+	}
+	return &cdcChunker{min: min, avg: avg, max: max, mask: uint64(avg - 1)}, nil
+}
+
+// feed advances the chunker by one byte and reports whether a chunk boundary
+// falls immediately after it.
+func (c *cdcChunker) feed(b byte) (boundary bool) {
+	c.n++
+
+	out := c.window[c.wpos]
+	c.window[c.wpos] = b
+	c.wpos = (c.wpos + 1) % cdcWindow
+	if c.filled < cdcWindow {
+		c.filled++
+		out = 0
+	}
+
+	c.hash = (c.hash << 1) ^ cdcTable[b] ^ cdcTable[out]
+
+	if c.n < c.min {
+		return false
+	}
+	if c.n >= c.max {
+		c.n = 0
+		return true
+	}
+	if c.hash&c.mask == 0 {
+		c.n = 0
+		return true
+	}
+	return false
+}
+
+// WithContentDefinedChunking switches the Writer from fixed WriterBlockSize
+// splitting to content-defined chunking: block boundaries are chosen by a
+// rolling hash over the input so that edits localized to one part of the
+// stream only change the blocks touching that edit, which keeps the rest
+// of the stream's compressed blocks byte-identical (and thus dedupable
+// against a previous version).
+func WithContentDefinedChunking(minSize, avgSize, maxSize int) WriterOption {
+	return func(w *Writer) error {
+		c, err := newCDCChunker(minSize, avgSize, maxSize)
+		if err != nil {
+			return err
+		}
+		w.cdc = c
+		return nil
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package filepathx extends path/filepath's Glob with "**" support, for the
+// mz CLI's file arguments.
+package filepathx
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Glob is filepath.Glob extended to treat a "**" path element as "this
+// directory and every directory beneath it", the way shells with globstar
+// enabled do (e.g. "logs/**/*.log" matches *.log files at any depth under
+// logs). Patterns without "**" behave exactly like filepath.Glob.
+func Glob(pattern string) ([]string, error) {
+	idx := strings.Index(pattern, "**")
+	if idx < 0 {
+		return filepath.Glob(pattern)
+	}
+	base := filepath.Dir(pattern[:idx])
+	rest := strings.TrimPrefix(pattern[idx+2:], string(filepath.Separator))
+
+	var matches []string
+	err := filepath.WalkDir(base, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		sub, err := filepath.Glob(filepath.Join(path, rest))
+		if err != nil {
+			return err
+		}
+		matches = append(matches, sub...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package readahead provides a buffered io.ReadCloser that prefetches from
+// its source on a background goroutine, so the mz CLI's compress/decompress
+// loop never stalls waiting on a slow disk or network read while it still
+// has decoded work queued up.
+package readahead
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// block is one prefetched buffer (or the terminal error that ended
+// prefetching) handed from fill to Read.
+type block struct {
+	data []byte
+	err  error
+}
+
+// reader implements io.ReadCloser over the blocks produced by fill.
+type reader struct {
+	ch   chan block
+	done chan struct{}
+	once sync.Once
+
+	cur    []byte
+	curErr error
+}
+
+// NewReaderSize returns an io.ReadCloser that reads ahead of the caller by
+// prefetching from r on a background goroutine, buffering up to workers
+// blocks of size bytes each. Read blocks only until the next prefetched
+// block is ready, rather than waiting on r directly.
+func NewReaderSize(r io.Reader, workers, size int) (io.ReadCloser, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("readahead: invalid buffer size %d", size)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	rd := &reader{
+		ch:   make(chan block, workers),
+		done: make(chan struct{}),
+	}
+	go rd.fill(r, size)
+	return rd, nil
+}
+
+// fill reads size-byte blocks from r until it errors (including io.EOF),
+// publishing each to ch, and exits early if done is closed by Close.
+func (rd *reader) fill(r io.Reader, size int) {
+	defer close(rd.ch)
+	for {
+		buf := make([]byte, size)
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			select {
+			case rd.ch <- block{data: buf[:n]}:
+			case <-rd.done:
+				return
+			}
+		}
+		if err != nil {
+			if err == io.ErrUnexpectedEOF {
+				err = io.EOF
+			}
+			select {
+			case rd.ch <- block{err: err}:
+			case <-rd.done:
+			}
+			return
+		}
+	}
+}
+
+// Read implements io.Reader, serving bytes from the most recently
+// prefetched block and pulling the next one from ch once it's exhausted.
+func (rd *reader) Read(p []byte) (int, error) {
+	for len(rd.cur) == 0 {
+		if rd.curErr != nil {
+			return 0, rd.curErr
+		}
+		b, ok := <-rd.ch
+		if !ok {
+			rd.curErr = io.EOF
+			continue
+		}
+		if b.err != nil {
+			rd.curErr = b.err
+			continue
+		}
+		rd.cur = b.data
+	}
+	n := copy(p, rd.cur)
+	rd.cur = rd.cur[n:]
+	return n, nil
+}
+
+// Close stops the background prefetch goroutine. It's safe to call more
+// than once.
+func (rd *reader) Close() error {
+	rd.once.Do(func() { close(rd.done) })
+	return nil
+}
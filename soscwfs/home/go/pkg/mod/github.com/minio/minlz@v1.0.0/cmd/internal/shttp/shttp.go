@@ -0,0 +1,229 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package shttp provides a seekable reader over an HTTP(S) URL, for the mz
+// CLI's --tail and --offset flags: rather than downloading a multi-GB
+// compressed file just to read its tail, the first Read after a Seek issues
+// an HTTP Range request starting at the new position, as located through
+// the trailing MinLZ index.
+package shttp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Logger receives diagnostic messages from a Reader. Both methods follow
+// log.Printf-style formatting.
+type Logger interface {
+	Infof(format string, args ...interface{})
+	Debugf(format string, args ...interface{})
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Infof(string, ...interface{})  {}
+func (nopLogger) Debugf(string, ...interface{}) {}
+
+// Reader is an io.ReadSeekCloser over an HTTP(S) URL. A Seek to any new
+// position drops the currently open response body, and the next Read issues
+// a Range request from that position.
+//
+// If the server's HEAD response doesn't advertise "Accept-Ranges: bytes",
+// Reader falls back to downloading the whole body once (logged via Logger)
+// and serving reads and seeks from memory, so callers don't need to special
+// case servers without range support.
+type Reader struct {
+	URL    string
+	Logger Logger
+	Client *http.Client
+
+	size    int64
+	sized   bool
+	pos     int64
+	noRange bool
+	body    io.ReadCloser
+	full    []byte
+	closed  bool
+}
+
+// New returns a Reader for url. Call Size before Read/Seek to learn the
+// object's length and whether the server supports range requests.
+func New(url string) *Reader {
+	return &Reader{URL: url, Logger: nopLogger{}, Client: http.DefaultClient}
+}
+
+// Size returns the total size of the object, probing it with an HTTP HEAD
+// request. If the response doesn't advertise "Accept-Ranges: bytes", Reader
+// falls back to a single full download on first Read.
+func (r *Reader) Size() (int64, error) {
+	resp, err := r.Client.Head(r.URL)
+	if err != nil {
+		return 0, fmt.Errorf("shttp: HEAD %s: %w", r.URL, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("shttp: HEAD %s: unexpected status %s", r.URL, resp.Status)
+	}
+	if resp.Header.Get("Accept-Ranges") != "bytes" {
+		r.noRange = true
+		r.Logger.Infof("shttp: %s does not advertise Accept-Ranges: bytes; falling back to a full download", r.URL)
+	}
+	r.size = resp.ContentLength
+	r.sized = true
+	r.Logger.Debugf("shttp: %s is %d bytes, range requests: %v", r.URL, r.size, !r.noRange)
+	return r.size, nil
+}
+
+// Read implements io.Reader.
+func (r *Reader) Read(p []byte) (int, error) {
+	if r.closed {
+		return 0, errors.New("shttp: read after close")
+	}
+	if r.noRange {
+		if r.full == nil {
+			if err := r.fetchFull(); err != nil {
+				return 0, err
+			}
+		}
+		if r.pos >= int64(len(r.full)) {
+			return 0, io.EOF
+		}
+		n := copy(p, r.full[r.pos:])
+		r.pos += int64(n)
+		return n, nil
+	}
+	if r.body == nil {
+		if err := r.openRange(r.pos); err != nil {
+			return 0, err
+		}
+		if r.noRange {
+			return r.Read(p)
+		}
+	}
+	n, err := r.body.Read(p)
+	r.pos += int64(n)
+	if err == io.EOF {
+		r.body.Close()
+		r.body = nil
+	}
+	return n, err
+}
+
+// Seek implements io.Seeker.
+func (r *Reader) Seek(offset int64, whence int) (int64, error) {
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = r.pos + offset
+	case io.SeekEnd:
+		if !r.sized {
+			if _, err := r.Size(); err != nil {
+				return 0, err
+			}
+		}
+		abs = r.size + offset
+	default:
+		return 0, errors.New("shttp: invalid whence")
+	}
+	if abs < 0 {
+		return 0, errors.New("shttp: negative seek position")
+	}
+	if abs != r.pos && r.body != nil {
+		r.body.Close()
+		r.body = nil
+	}
+	r.pos = abs
+	return r.pos, nil
+}
+
+// Close implements io.Closer.
+func (r *Reader) Close() error {
+	r.closed = true
+	r.full = nil
+	if r.body != nil {
+		err := r.body.Close()
+		r.body = nil
+		return err
+	}
+	return nil
+}
+
+// openRange issues a GET request for bytes [start, EOF) and keeps the
+// response body open for subsequent sequential reads. Some servers
+// advertise "Accept-Ranges: bytes" on HEAD but still answer a ranged GET
+// with a full 200 OK; rather than erroring out, that response is treated
+// the same as noRange: the body is buffered in full and served from
+// memory, so a mid-stream server quirk doesn't fail the whole read.
+func (r *Reader) openRange(start int64) error {
+	req, err := http.NewRequest(http.MethodGet, r.URL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-", start))
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("shttp: GET %s: %w", r.URL, err)
+	}
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		r.Logger.Debugf("shttp: %s opened range request at offset %d", r.URL, start)
+		r.body = resp.Body
+		return nil
+	case http.StatusOK:
+		defer resp.Body.Close()
+		r.Logger.Infof("shttp: %s returned 200 OK for a range request; falling back to a full download", r.URL)
+		r.noRange = true
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		r.full = b
+		if !r.sized {
+			r.size = int64(len(b))
+			r.sized = true
+		}
+		return nil
+	default:
+		resp.Body.Close()
+		return fmt.Errorf("shttp: GET %s: expected 206 Partial Content, got %s", r.URL, resp.Status)
+	}
+}
+
+// fetchFull downloads the whole object once, for servers that don't support
+// range requests.
+func (r *Reader) fetchFull() error {
+	resp, err := r.Client.Get(r.URL)
+	if err != nil {
+		return fmt.Errorf("shttp: GET %s: %w", r.URL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("shttp: GET %s: unexpected status %s", r.URL, resp.Status)
+	}
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	r.full = b
+	if !r.sized {
+		r.size = int64(len(b))
+		r.sized = true
+	}
+	return nil
+}
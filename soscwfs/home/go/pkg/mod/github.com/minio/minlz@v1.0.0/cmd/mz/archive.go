@@ -0,0 +1,123 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/minio/minlz"
+	"github.com/minio/minlz/mzarchive"
+)
+
+// compressArchive walks every entry in args (a file or a directory, walked
+// recursively) and adds each regular file it finds to a new mzarchive
+// container at dstFilename, skipping any member whose slash-separated path
+// matches one of excludes. Member names are relative to the current
+// directory, so callers wanting archive-relative names should chdir (-C)
+// before calling this.
+func compressArchive(args []string, dstFilename string, opts []minlz.WriterOption, quiet, safe *bool, excludes []string) {
+	if *safe {
+		if _, err := os.Stat(dstFilename); !os.IsNotExist(err) {
+			exitErr(errors.New("destination file exists"))
+		}
+	}
+	dstFile, err := os.OpenFile(dstFilename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	exitErr(err)
+	defer dstFile.Close()
+	bw := bufio.NewWriterSize(dstFile, 4<<20)
+
+	aw := mzarchive.NewWriter(bw, opts...)
+	for _, arg := range args {
+		exitErr(filepath.WalkDir(arg, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			name := filepath.ToSlash(p)
+			if matchesAny(excludes, name) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() || !d.Type().IsRegular() {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			file, err := os.Open(p)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			if !*quiet {
+				fmt.Println("Adding", name)
+			}
+			_, err = aw.Add(name, info.Mode(), info.ModTime(), file)
+			return err
+		}))
+	}
+	exitErr(aw.Close())
+	exitErr(bw.Flush())
+	if !*quiet {
+		fmt.Printf("Wrote %d entries -> %s\n", len(aw.Entries()), dstFilename)
+	}
+}
+
+// matchesAny reports whether name matches any of the path.Match glob
+// patterns, the same matching mainExtract uses for its path-glob argument.
+func matchesAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// extractArchive restores every entry of ar matching pattern under outDir,
+// recreating parent directories as needed and restoring each entry's mode
+// and modification time. It returns the number of entries matched.
+func extractArchive(ar *mzarchive.Reader, pattern, outDir string, cpu int) int {
+	var matched int
+	for _, e := range ar.Entries() {
+		ok, err := path.Match(pattern, e.Name)
+		exitErr(err)
+		if !ok {
+			continue
+		}
+		matched++
+
+		dst := filepath.Join(outDir, filepath.FromSlash(e.Name))
+		exitErr(os.MkdirAll(filepath.Dir(dst), 0o755))
+
+		f, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, e.Mode)
+		exitErr(err)
+		_, err = ar.Extract(e.Name, f, cpu)
+		cerr := f.Close()
+		exitErr(err)
+		exitErr(cerr)
+		exitErr(os.Chtimes(dst, e.ModTime, e.ModTime))
+		fmt.Println(dst)
+	}
+	return matched
+}
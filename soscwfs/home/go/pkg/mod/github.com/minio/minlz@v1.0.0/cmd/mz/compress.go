@@ -33,6 +33,7 @@ import (
 	"github.com/minio/minlz"
 	"github.com/minio/minlz/cmd/internal/filepathx"
 	"github.com/minio/minlz/cmd/internal/readahead"
+	"github.com/minio/minlz/mztar"
 )
 
 func mainCompress(args []string) {
@@ -44,8 +45,10 @@ func mainCompress(args []string) {
 		slower    = fs.Bool("3", false, "Compress more, but a lot slower")
 		recomp    = fs.Bool("recomp", false, "Recompress MinLZ, Snappy or S2 input")
 		blockSize = fs.String("bs", "8M", "Max block size. Examples: 64K, 256K, 1M, 8M. Must be power of two and <= 8MB")
+		cdc       = fs.String("cdc", "", "Use content-defined chunking instead of fixed-size blocks: avg (e.g. 64K), or min:avg:max (e.g. 16K:64K:256K). Block boundaries then follow a rolling hash over the input instead of -bs, so unrelated edits elsewhere in the file don't change a block's compressed bytes")
 		index     = fs.Bool("index", true, "Add seek index")
 		padding   = fs.String("pad", "1", "Pad size to a multiple of this value, Examples: 500, 64K, 256K, 1M, 4M, etc")
+		dictFile  = fs.String("dict", "", "Seed the encoder with a dictionary trained by 'train'. A matching -dict is required to decompress the result")
 
 		// Shared
 		block  = fs.Bool("block", false, "Use as a single block. Will load content into memory. Max 8MB.")
@@ -58,6 +61,13 @@ func mainCompress(args []string) {
 		bench  = fs.Int("bench", 0, "Run benchmark n times. No output will be written")
 		verify = fs.Bool("verify", false, "Verify files, but do not write output")
 		help   = fs.Bool("help", false, "Display help")
+
+		tarSplit = fs.Bool("tar-split", false, "Input is a tar stream: split headers/padding from file content so a matching -tar-split decompress reproduces it byte-for-byte")
+		tarTOC   = fs.Bool("tar-toc", false, "Input is a tar stream: start a fresh block at every entry and append a table of contents, so 'list'/'extract' can fetch members by name without decoding the whole stream")
+
+		archive     = fs.String("archive", "", "Recursively add input director(y/ies) to a MinLZ-native archive container instead of compressing each file separately. Only supported value: \"mz\" (see mzarchive.Writer)")
+		archiveRoot = fs.String("C", "", "Change to this directory before walking input for -archive, so member names are relative to it")
+		exclude     = fs.String("exclude", "", "Comma-separated glob patterns matched against the slash-separated member name; matching files (or directories, pruning their contents) are skipped by -archive")
 	)
 	fs.Usage = func() {
 		w := fs.Output()
@@ -72,6 +82,36 @@ Directories can be wildcards as well. testdir/*/*.txt will match testdir/subdir/
 File names beginning with 'http://' and 'https://' will be downloaded and compressed.
 Only http response code 200 is accepted.
 
+-tar-split treats the input as a tar stream, splitting it into file content
+(compressed as normal blocks) and a packer side-stream of headers/padding
+(see mztar.NewSplittingWriter), so a -tar-split decompress reproduces it
+byte-for-byte. Incompatible with -block and -recomp.
+
+-tar-toc also treats the input as a tar stream, but instead of reproducing
+it byte-for-byte, it records where each entry landed (see
+mztar.NewTOCWriter) so the 'list' and 'extract' subcommands can fetch
+members by name, fetching and decoding only the block(s) each one covers.
+Incompatible with -block, -recomp and -tar-split.
+
+-cdc switches block segmentation from fixed -bs splitting to
+content-defined chunking (see minlz.WithContentDefinedChunking): two
+versions of a file that only differ in one place then still produce
+mostly byte-identical compressed blocks elsewhere, which downstream
+dedup (rsync/casync-style) can take advantage of.
+
+-dict seeds the encoder's match window with a dictionary produced by the
+'train' subcommand, for better ratio on many small, structurally similar
+files. The same dictionary file must be passed to -dict on decompress.
+
+-archive mz recursively adds every regular file under the input
+director(y/ies) to a single MinLZ-native archive (see mzarchive.Writer)
+instead of compressing each one separately: a central directory trails the
+compressed entries, so 'list'/'extract' can fetch members by name, and
+every entry is its own independent MinLZ stream that can be decoded with
+minlz.Reader.DecodeConcurrent. Use -C to make member names relative to a
+directory other than the current one, and -exclude to skip matching paths.
+Incompatible with -block, -recomp, -tar-split and -tar-toc.
+
 Options:`)
 		fs.PrintDefaults()
 		fmt.Fprintf(w, "\nUsage: %v c [options] <input>\n", os.Args[0])
@@ -91,6 +131,27 @@ Options:`)
 		fs.Usage()
 		os.Exit(1)
 	}
+	if *tarSplit && *block {
+		exitErr(errors.New("-tar-split and -block cannot be used together"))
+	}
+	if *tarSplit && *recomp {
+		exitErr(errors.New("-tar-split and -recomp cannot be used together"))
+	}
+	if *tarTOC && *block {
+		exitErr(errors.New("-tar-toc and -block cannot be used together"))
+	}
+	if *tarTOC && *recomp {
+		exitErr(errors.New("-tar-toc and -recomp cannot be used together"))
+	}
+	if *tarTOC && *tarSplit {
+		exitErr(errors.New("-tar-toc and -tar-split cannot be used together"))
+	}
+	if *archive != "" && (*block || *recomp || *tarSplit || *tarTOC) {
+		exitErr(errors.New("-archive cannot be combined with -block, -recomp, -tar-split or -tar-toc"))
+	}
+	if *archive != "" && *archive != "mz" {
+		exitErr(fmt.Errorf("-archive: unsupported value %q, want \"mz\"", *archive))
+	}
 	level := minlz.LevelBalanced
 	if *faster {
 		level = minlz.LevelFastest
@@ -99,6 +160,35 @@ Options:`)
 		level = minlz.LevelSmallest
 	}
 	opts := []minlz.WriterOption{minlz.WriterBlockSize(int(sz)), minlz.WriterConcurrency(*cpu), minlz.WriterPadding(int(pad)), minlz.WriterLevel(level), minlz.WriterAddIndex(*index)}
+	if *cdc != "" {
+		min, avg, max, err := parseCDC(*cdc)
+		exitErr(err)
+		opts = append(opts, minlz.WithContentDefinedChunking(min, avg, max))
+	}
+	var dict *minlz.Dict
+	if *dictFile != "" {
+		dict = loadDictFile(*dictFile)
+		opts = append(opts, minlz.WithDict(dict))
+	}
+	if *archive != "" {
+		if len(args) == 0 {
+			fs.Usage()
+			os.Exit(1)
+		}
+		if *archiveRoot != "" {
+			exitErr(os.Chdir(*archiveRoot))
+		}
+		var excludes []string
+		if *exclude != "" {
+			excludes = strings.Split(*exclude, ",")
+		}
+		dst := *out
+		if dst == "" {
+			dst = "archive" + minlzExt
+		}
+		compressArchive(args, dst, opts, quiet, safe, excludes)
+		return
+	}
 	wr := minlz.NewWriter(nil, opts...)
 
 	// No args, use stdin/stdout
@@ -106,9 +196,8 @@ Options:`)
 		// Catch interrupt, so we don't exit at once.
 		// os.Stdin will return EOF, so we should be able to get everything.
 		signal.Notify(make(chan os.Signal, 1), os.Interrupt)
-		if len(*out) == 0 {
-			wr.Reset(os.Stdout)
-		} else {
+		var dst io.Writer = os.Stdout
+		if len(*out) != 0 {
 			if *safe {
 				_, err := os.Stat(*out)
 				if !os.IsNotExist(err) {
@@ -120,8 +209,23 @@ Options:`)
 			defer dstFile.Close()
 			bw := bufio.NewWriterSize(dstFile, int(sz*2))
 			defer bw.Flush()
-			wr.Reset(bw)
+			dst = bw
+		}
+		if *tarSplit {
+			sw := mztar.NewSplittingWriter(dst, opts...)
+			_, err = io.Copy(sw, os.Stdin)
+			printErr(err)
+			printErr(sw.Close())
+			return
 		}
+		if *tarTOC {
+			tw := mztar.NewTOCWriter(dst, opts...)
+			_, err = io.Copy(tw, os.Stdin)
+			printErr(err)
+			printErr(tw.Close())
+			return
+		}
+		wr.Reset(dst)
 		_, err = wr.ReadFrom(os.Stdin)
 		printErr(err)
 		printErr(wr.Close())
@@ -156,14 +260,50 @@ Options:`)
 	}
 	for _, filename := range files {
 		if *block {
-			processBlock(recomp, filename, ext, out, quiet, err, stdout, safe, level, verify, remove)
+			processBlock(recomp, filename, ext, out, quiet, err, stdout, safe, level, verify, remove, dict)
 		} else {
-			processStream(filename, recomp, ext, out, quiet, stdout, remove, err, cpu, safe, sz, verify, wr)
+			processStream(filename, recomp, ext, out, quiet, stdout, remove, err, cpu, safe, sz, verify, wr, tarSplit, tarTOC, opts)
+		}
+	}
+}
+
+// parseCDC parses the -cdc flag value into content-defined chunking bounds:
+// either a single avg size (min and max default to avg/4 and avg*4), or
+// explicit min:avg:max.
+func parseCDC(s string) (min, avg, max int, err error) {
+	parts := strings.Split(s, ":")
+	switch len(parts) {
+	case 1:
+		a, err := toSize(parts[0])
+		if err != nil {
+			return 0, 0, 0, err
 		}
+		avg = int(a)
+		min, max = avg/4, avg*4
+	case 3:
+		lo, err := toSize(parts[0])
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		a, err := toSize(parts[1])
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		hi, err := toSize(parts[2])
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		min, avg, max = int(lo), int(a), int(hi)
+	default:
+		return 0, 0, 0, fmt.Errorf("-cdc must be avg or min:avg:max, got %q", s)
+	}
+	if max > minlz.MaxBlockSize {
+		max = minlz.MaxBlockSize
 	}
+	return min, avg, max, nil
 }
 
-func processStream(filename string, recomp *bool, ext string, outFile *string, quiet *bool, stdout *bool, remove *bool, err error, cpu *int, safe *bool, sz int64, verify *bool, wr *minlz.Writer) {
+func processStream(filename string, recomp *bool, ext string, outFile *string, quiet *bool, stdout *bool, remove *bool, err error, cpu *int, safe *bool, sz int64, verify *bool, wr *minlz.Writer, tarSplit *bool, tarTOC *bool, wopts []minlz.WriterOption) {
 	var closeOnce sync.Once
 	outFileBase := filename
 	if *recomp {
@@ -242,11 +382,23 @@ func processStream(filename string, recomp *bool, ext string, outFile *string, q
 	out, errFn := verifyTo(out, *verify, *quiet, *cpu)
 	wc := wCounter{out: out}
 	start := time.Now()
-	wr.Reset(&wc)
-	defer wr.Close()
-	_, err = wr.ReadFrom(src)
-	exitErr(err)
-	err = wr.Close()
+	if *tarSplit {
+		sw := mztar.NewSplittingWriter(&wc, wopts...)
+		_, err = io.Copy(sw, src)
+		exitErr(err)
+		err = sw.Close()
+	} else if *tarTOC {
+		tw := mztar.NewTOCWriter(&wc, wopts...)
+		_, err = io.Copy(tw, src)
+		exitErr(err)
+		err = tw.Close()
+	} else {
+		wr.Reset(&wc)
+		defer wr.Close()
+		_, err = wr.ReadFrom(src)
+		exitErr(err)
+		err = wr.Close()
+	}
 
 	exitErr(err)
 	if !*quiet {
@@ -269,7 +421,7 @@ func processStream(filename string, recomp *bool, ext string, outFile *string, q
 	}
 }
 
-func processBlock(recomp *bool, filename string, ext string, outFile *string, quiet *bool, err error, stdout *bool, safe *bool, level int, verify *bool, remove *bool) {
+func processBlock(recomp *bool, filename string, ext string, outFile *string, quiet *bool, err error, stdout *bool, safe *bool, level minlz.Level, verify *bool, remove *bool, dict *minlz.Dict) {
 	if *recomp {
 		exitErr(errors.New("cannot recompress blocks (yet)"))
 	}
@@ -309,7 +461,12 @@ func processBlock(recomp *bool, filename string, ext string, outFile *string, qu
 			out = dstFile
 		}
 		start := time.Now()
-		compressed, err := minlz.Encode(nil, inBytes, level)
+		var compressed []byte
+		if dict != nil {
+			compressed, err = minlz.EncodeDict(nil, inBytes, dict.Bytes(), level)
+		} else {
+			compressed, err = minlz.Encode(nil, inBytes, level)
+		}
 		exitErr(err)
 		_, err = out.Write(compressed)
 		exitErr(err)
@@ -320,7 +477,12 @@ func processBlock(recomp *bool, filename string, ext string, outFile *string, qu
 			fmt.Printf(" %d -> %d [%.02f%%]; %.01fMB/s\n", len(inBytes), len(compressed), pct, mbpersec)
 		}
 		if *verify {
-			got, err := minlz.Decode(make([]byte, 0, len(inBytes)), compressed)
+			var got []byte
+			if dict != nil {
+				got, err = minlz.DecodeDict(make([]byte, 0, len(inBytes)), compressed, dict.Bytes())
+			} else {
+				got, err = minlz.Decode(make([]byte, 0, len(inBytes)), compressed)
+			}
 			exitErr(err)
 			if !bytes.Equal(got, inBytes) {
 				exitErr(fmt.Errorf("decoded content mismatch"))
@@ -342,7 +504,7 @@ func processBlock(recomp *bool, filename string, ext string, outFile *string, qu
 	}()
 }
 
-func runBench(files []string, block *bool, quiet *bool, err error, bench *int, level int, cpu *int, verify *bool, wr *minlz.Writer) error {
+func runBench(files []string, block *bool, quiet *bool, err error, bench *int, level minlz.Level, cpu *int, verify *bool, wr *minlz.Writer) error {
 	debug.SetGCPercent(10)
 	dec := minlz.NewReader(nil)
 	for _, filename := range files {
@@ -468,7 +630,7 @@ func runBenchStream(quiet *bool, filename string, err error, bench *int, verify
 	return err
 }
 
-func runBenchBlock(quiet *bool, filename string, err error, bench *int, level int, cpu *int, verify *bool, wr *minlz.Writer) error {
+func runBenchBlock(quiet *bool, filename string, err error, bench *int, level minlz.Level, cpu *int, verify *bool, wr *minlz.Writer) error {
 	if !*quiet {
 		fmt.Print("Reading ", filename, "...")
 	}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"regexp"
 	"runtime"
 	"runtime/debug"
 	"strconv"
@@ -34,6 +35,7 @@ import (
 	"github.com/minio/minlz"
 	"github.com/minio/minlz/cmd/internal/filepathx"
 	"github.com/minio/minlz/cmd/internal/readahead"
+	"github.com/minio/minlz/mztar"
 )
 
 const maxBlockSize = 8 << 20
@@ -60,14 +62,26 @@ func mainDecompress(args []string, cat, tail bool) {
 		quiet      = fs.Bool("q", false, "Don't write any output to terminal, except errors")
 		help       = fs.Bool("help", false, "Display help")
 		verify     = fs.Bool("verify", false, "Verify files, but do not write output")
+		member     = fs.String("member", "*", "Glob selecting which member(s) to extract, when input is a .zip archive")
+		jobs       = fs.Int("jobs", 1, "Decompress this many files in parallel, when given more than one input file")
+		tarSplit   = fs.Bool("tar-split", false, "Input was compressed with -tar-split: reassemble the original tar stream byte-for-byte (see mztar.NewAssembler)")
+		dictFile   = fs.String("dict", "", "Dictionary file the input was compressed with (see 'train' and -dict on 'c')")
+
+		grepPattern = fs.String("grep", "", "Only emit decoded lines matching this regexp")
+		grepV       = fs.Bool("grep-v", false, "Invert --grep: only emit lines NOT matching")
+		grepBefore  = fs.Int("before", 0, "With --grep, also emit N lines preceding each match")
+		grepAfter   = fs.Int("after", 0, "With --grep, also emit N lines following each match")
+		grepMaxLine = fs.Int("grep-max-line", 1<<20, "Maximum buffered line length for --grep, in bytes")
 	)
 
-	var offsetString *string
+	var offsetString, rangeString *string
 	if !tail {
 		offsetString = fs.String("offset", "", "Start at offset. Examples: 92, 64K, 256K, 1M, 4M. Requires Index")
+		rangeString = fs.String("range", "", "Return decompressed bytes [start:end). Examples: 0:1024, 4M:8M. Shorthand for -offset start -limit (end-start). Requires Index")
 	} else {
 		var s string
 		offsetString = &s
+		rangeString = &s
 	}
 	var bench *int
 	if cat || tail {
@@ -89,6 +103,26 @@ Directories can be wildcards as well. testdir/*/*.txt will match testdir/subdir/
 File names beginning with 'http://' and 'https://' will be downloaded and decompressed.
 Extensions on downloaded files are ignored. Only http response code 200 is accepted.
 
+A '.zip' input is read as an archive instead: every member using the minlz
+compression method (see minlzzip.Register) is extracted, filtered by -member.
+
+-grep filters decoded output to matching lines, like zgrep, without a shell
+pipeline. Combine with -tail/-offset/-range to scan only part of a large
+stream.
+
+-range start:end is shorthand for -offset start -limit (end-start): given a
+file written with an index (see WriterAddIndex), and an http:// or https://
+source, only the index footer and the blocks covering [start:end) are
+range-fetched, not the whole object. Servers that don't advertise
+Accept-Ranges are downloaded in full instead (see shttp.Reader).
+
+-tar-split reassembles a tar stream compressed with -tar-split byte-for-byte
+(see mztar.NewAssembler). Incompatible with -block, -tail, -offset, -limit
+and -grep.
+
+-dict must be given the same dictionary file passed to -dict on 'c' if the
+input was compressed with one; otherwise decoding fails.
+
 Options:`)
 		fs.PrintDefaults()
 		fmt.Fprintf(w, "\nUsage: %v %s [options] <input>\n", os.Args[0], os.Args[1])
@@ -127,14 +161,61 @@ Options:`)
 		limitBytes, err = toSize(*limitString)
 		exitErr(err)
 	}
+	if *rangeString != "" {
+		if tailBytes > 0 || offset > 0 || limitBytes > 0 {
+			exitErr(errors.New("--range cannot be combined with --offset, --tail or --limit"))
+		}
+		startStr, endStr, ok := strings.Cut(*rangeString, ":")
+		if !ok {
+			exitErr(errors.New("--range must be of the form start:end, e.g. 0:1024"))
+		}
+		start, err := toSize(startStr)
+		exitErr(err)
+		end, err := toSize(endStr)
+		exitErr(err)
+		if end <= start {
+			exitErr(errors.New("--range end must be greater than start"))
+		}
+		offset, limitBytes = start, end-start
+	}
 
 	*block = *block || *blockDebug
-	r := minlz.NewReader(nil, minlz.ReaderFallback(true))
+	if *tarSplit && (*block || tailBytes > 0 || offset > 0 || limitBytes > 0 || *grepPattern != "") {
+		exitErr(errors.New("-tar-split cannot be combined with -block, -tail, -offset, -limit or -grep"))
+	}
+	var dict *minlz.Dict
+	if *dictFile != "" {
+		dict = loadDictFile(*dictFile)
+	}
+	r := minlz.NewReader(nil, readerOpts(dict)...)
 
 	if len(args) == 1 && args[0] == "-" {
 		if limitBytes > 0 || offset > 0 || tailBytes > 0 {
 			exitErr(errors.New("--offset, --tail and --limit cannot be used with stdin"))
 		}
+		if *tarSplit {
+			a := mztar.NewAssembler(os.Stdin)
+			if *out == "" {
+				_, err := io.Copy(os.Stdout, a)
+				exitErr(err)
+				return
+			}
+			dstFilename := *out
+			if *safe {
+				_, err := os.Stat(dstFilename)
+				if !os.IsNotExist(err) {
+					exitErr(errors.New("destination files exists"))
+				}
+			}
+			dstFile, err := os.OpenFile(dstFilename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+			exitErr(err)
+			defer dstFile.Close()
+			bw := bufio.NewWriterSize(dstFile, 4<<20)
+			defer bw.Flush()
+			_, err = io.Copy(bw, a)
+			exitErr(err)
+			return
+		}
 		if *block {
 			all, err := io.ReadAll(io.LimitReader(os.Stdin, int64(minlz.MaxEncodedLen(minlz.MaxBlockSize))))
 			exitErr(err)
@@ -143,7 +224,12 @@ Options:`)
 				DecodeDebug(nil, all)
 				os.Exit(0)
 			}
-			b, err := minlz.Decode(nil, all)
+			var b []byte
+			if dict != nil {
+				b, err = minlz.DecodeDict(nil, all, dict.Bytes())
+			} else {
+				b, err = minlz.Decode(nil, all)
+			}
 			exitErr(err)
 			_, err = os.Stdout.Write(b)
 			exitErr(err)
@@ -194,6 +280,13 @@ Options:`)
 
 	*quiet = *quiet || *stdout
 
+	var grep *grepOptions
+	if *grepPattern != "" {
+		re, err := regexp.Compile(*grepPattern)
+		exitErr(err)
+		grep = &grepOptions{pattern: re, invert: *grepV, before: *grepBefore, after: *grepAfter, maxLine: *grepMaxLine}
+	}
+
 	if *bench > 0 {
 		if limitBytes > 0 || offset > 0 || tailBytes > 0 {
 			exitErr(errors.New("--offset, --tail and --limit cannot be used with benchmarks"))
@@ -206,7 +299,17 @@ Options:`)
 		exitErr(errors.New("-out parameter can only be used with one input"))
 	}
 
+	var work []decompressJob
 	for _, filename := range files {
+		if strings.HasSuffix(strings.ToLower(filename), zipExt) {
+			dstDir := "."
+			if *out != "" {
+				dstDir = *out
+			}
+			decompressZip(quiet, filename, dstDir, *member, stdout, safe, verify, remove, cpu, tailBytes, offset, limitBytes, tailNextNL, limitNextNL)
+			continue
+		}
+
 		dstFilename := cleanFileName(filename)
 		block := *block
 		if strings.HasSuffix(dstFilename, minlzBlockExt) {
@@ -238,14 +341,191 @@ Options:`)
 		if *verify {
 			dstFilename = "(verify)"
 		}
-		decompressFile(quiet, filename, dstFilename, block, tailBytes, offset, safe, verify, stdout, blockDebug, tailNextNL, r, limitBytes, limitNextNL, cpu, remove)
+		work = append(work, decompressJob{filename: filename, dstFilename: dstFilename, block: block})
+	}
+
+	runDecompressJobs(work, *jobs, quiet, tailBytes, offset, safe, verify, stdout, blockDebug, tailNextNL, limitBytes, limitNextNL, cpu, remove, grep, tarSplit, dict)
+}
+
+// readerOpts builds the ReaderOption slice shared by every minlz.NewReader
+// call site in this file, adding ReaderDict only when a -dict was given.
+func readerOpts(dict *minlz.Dict) []minlz.ReaderOption {
+	opts := []minlz.ReaderOption{minlz.ReaderFallback(true)}
+	if dict != nil {
+		opts = append(opts, minlz.ReaderDict(dict))
+	}
+	return opts
+}
+
+// decompressJob is one plain (non-zip) input file queued by mainDecompress,
+// with its destination already resolved.
+type decompressJob struct {
+	filename    string
+	dstFilename string
+	block       bool
+}
+
+// runDecompressJobs processes work, either one at a time (jobs <= 1, a
+// single file, or nothing to parallelize) exactly as mainDecompress always
+// has, or through a worker pool of the given size. In the pool case,
+// progress lines are serialized through a single printer so concurrent
+// files' output doesn't interleave, and -c/stdout output is buffered per
+// job and flushed to os.Stdout in work's original order, bgzip/pigz-style,
+// once every earlier job has flushed.
+func runDecompressJobs(work []decompressJob, jobs int, quiet *bool, tailBytes, offset int64, safe, verify, stdout, blockDebug *bool, tailNextNL bool, limitBytes int64, limitNextNL bool, cpu *int, remove *bool, grep *grepOptions, tarSplit *bool, dict *minlz.Dict) {
+	if jobs < 1 {
+		jobs = 1
+	}
+	if jobs == 1 || len(work) <= 1 {
+		r := minlz.NewReader(nil, readerOpts(dict)...)
+		for _, j := range work {
+			decompressFile(quiet, j.filename, j.dstFilename, j.block, tailBytes, offset, safe, verify, stdout, blockDebug, tailNextNL, r, limitBytes, limitNextNL, cpu, remove, nil, nil, grep, tarSplit, dict)
+		}
+		return
 	}
+
+	pr := newPrinter()
+	defer pr.close()
+
+	// done[i] becomes true, and tmp[i] holds the path of that job's
+	// captured stdout (empty if it wrote straight to its destination file),
+	// once job i has completed; the flusher below drains them in order.
+	var mu sync.Mutex
+	cond := sync.NewCond(&mu)
+	done := make([]bool, len(work))
+	tmp := make([]string, len(work))
+
+	var flushWG sync.WaitGroup
+	if *stdout {
+		flushWG.Add(1)
+		go func() {
+			defer flushWG.Done()
+			mu.Lock()
+			defer mu.Unlock()
+			for i := range work {
+				for !done[i] {
+					cond.Wait()
+				}
+				if tmp[i] != "" {
+					mu.Unlock()
+					f, err := os.Open(tmp[i])
+					exitErr(err)
+					_, err = io.Copy(os.Stdout, f)
+					f.Close()
+					exitErr(err)
+					exitErr(os.Remove(tmp[i]))
+					mu.Lock()
+				}
+			}
+		}()
+	}
+
+	sem := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+	for i, j := range work {
+		i, j := i, j
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			r := minlz.NewReader(nil, readerOpts(dict)...)
+			var capture *os.File
+			if *stdout {
+				f, err := os.CreateTemp("", "mz-decompress-*")
+				exitErr(err)
+				capture = f
+			}
+			decompressFile(quiet, j.filename, j.dstFilename, j.block, tailBytes, offset, safe, verify, stdout, blockDebug, tailNextNL, r, limitBytes, limitNextNL, cpu, remove, pr, capture, grep, tarSplit, dict)
+			if capture != nil {
+				capture.Close()
+			}
+
+			mu.Lock()
+			done[i] = true
+			if capture != nil {
+				tmp[i] = capture.Name()
+			}
+			cond.Broadcast()
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	flushWG.Wait()
+}
+
+// printer serializes progress lines from concurrent decompressFile calls
+// through a single goroutine, so two files' "Decompressing X -> Y ..."
+// lines can never interleave mid-line the way unsynchronized concurrent
+// fmt.Print calls could.
+type printer struct {
+	lines chan string
+	done  chan struct{}
 }
 
-func decompressFile(quiet *bool, filename string, dstFilename string, block bool, tailBytes int64, offset int64, safe *bool, verify *bool, stdout *bool, blockDebug *bool, tailNextNL bool, r *minlz.Reader, limitBytes int64, limitNextNL bool, cpu *int, remove *bool) {
+func newPrinter() *printer {
+	p := &printer{lines: make(chan string, 64), done: make(chan struct{})}
+	go func() {
+		defer close(p.done)
+		for line := range p.lines {
+			fmt.Print(line)
+		}
+	}()
+	return p
+}
+
+func (p *printer) Printf(format string, args ...interface{}) {
+	p.lines <- fmt.Sprintf(format, args...)
+}
+
+func (p *printer) close() {
+	close(p.lines)
+	<-p.done
+}
+
+// decompressFile decompresses one input file. pr, if non-nil, receives
+// progress lines instead of decompressFile printing them directly, so
+// runDecompressJobs can serialize output from several concurrent calls
+// through a single goroutine; pass nil to print directly, as the serial
+// path does. capture, if non-nil, receives the decoded bytes in place of
+// os.Stdout when stdout is set, so runDecompressJobs can flush completed
+// jobs to the real stdout in argument order instead of letting them race
+// each other there; pass nil to write straight to os.Stdout, as the serial
+// path does. grep, if non-nil, filters decoded to matching lines (see
+// grepOptions) before --limit is applied, so --limit bounds the filtered
+// output rather than the raw decoded stream.
+func decompressFile(quiet *bool, filename string, dstFilename string, block bool, tailBytes int64, offset int64, safe *bool, verify *bool, stdout *bool, blockDebug *bool, tailNextNL bool, r *minlz.Reader, limitBytes int64, limitNextNL bool, cpu *int, remove *bool, pr *printer, capture *os.File, grep *grepOptions, tarSplit *bool, dict *minlz.Dict) {
 	var closeOnce sync.Once
+	// progress accumulates this file's status line when pr is set, so the
+	// whole line reaches the printer as one message and can't be split by
+	// another file's line landing in between; with no printer, lines are
+	// still printed incrementally (start, then stats) as they always were.
+	var progress strings.Builder
+	printf := func(format string, args ...interface{}) {
+		if pr != nil {
+			pr.Printf(format, args...)
+		} else {
+			fmt.Printf(format, args...)
+		}
+	}
+	printStart := func(format string, args ...interface{}) {
+		if pr != nil {
+			fmt.Fprintf(&progress, format, args...)
+		} else {
+			fmt.Printf(format, args...)
+		}
+	}
+	printEnd := func(format string, args ...interface{}) {
+		if pr != nil {
+			fmt.Fprintf(&progress, format, args...)
+			pr.Printf("%s", progress.String())
+		} else {
+			fmt.Printf(format, args...)
+		}
+	}
 	if !*quiet {
-		fmt.Print("Decompressing ", filename, " -> ", dstFilename)
+		printStart("Decompressing %s -> %s", filename, dstFilename)
 	}
 	seeker := !block && (tailBytes > 0 || offset > 0)
 	// Input file.
@@ -287,6 +567,8 @@ func decompressFile(quiet *bool, filename string, dstFilename string, block bool
 	switch {
 	case *verify:
 		out = io.Discard
+	case *stdout && capture != nil:
+		out = capture
 	case *stdout:
 		out = os.Stdout
 	default:
@@ -302,13 +584,20 @@ func decompressFile(quiet *bool, filename string, dstFilename string, block bool
 	}
 	var decoded io.Reader
 	start := time.Now()
-	if block {
+	if *tarSplit {
+		decoded = mztar.NewAssembler(src)
+	} else if block {
 		all, err := io.ReadAll(src)
 		exitErr(err)
 		if *blockDebug {
 			DecodeDebug(nil, all)
 		}
-		b, err := minlz.Decode(nil, all)
+		var b []byte
+		if dict != nil {
+			b, err = minlz.DecodeDict(nil, all, dict.Bytes())
+		} else {
+			b, err = minlz.Decode(nil, all)
+		}
 		if offset > 0 {
 			b = b[min(offset, int64(len(all))):]
 		}
@@ -355,6 +644,9 @@ func decompressFile(quiet *bool, filename string, dstFilename string, block bool
 		}
 		decoded = r
 	}
+	if grep != nil {
+		decoded = newGrepReader(decoded, grep)
+	}
 	if limitBytes > 0 {
 		decoded = limitReaderNL(decoded, limitBytes, limitNextNL)
 	}
@@ -370,13 +662,13 @@ func decompressFile(quiet *bool, filename string, dstFilename string, block bool
 		elapsed := time.Since(start)
 		mbPerSec := (float64(output) / 1e6) / (float64(elapsed) / (float64(time.Second)))
 		pct := float64(output) * 100 / float64(rc.BytesRead())
-		fmt.Printf(" %d -> %d [%.02f%%]; %.01fMB/s\n", rc.BytesRead(), output, pct, mbPerSec)
+		printEnd(" %d -> %d [%.02f%%]; %.01fMB/s\n", rc.BytesRead(), output, pct, mbPerSec)
 	}
 	if *remove && !*verify {
 		closeOnce.Do(func() {
 			file.Close()
 			if !*quiet {
-				fmt.Println("Removing", filename)
+				printf("Removing %s\n", filename)
 			}
 			err := os.Remove(filename)
 			exitErr(err)
@@ -884,33 +1176,100 @@ func minLZDecodeDebug(dst, src []byte) int {
 // but stops with EOF after n bytes, and optionally waits for a '\n'.
 // The underlying implementation is a *LimitedReader.
 func limitReaderNL(r io.Reader, n int64, nextNL bool) io.Reader {
-	return &limitedReaderNL{R: r, N: n, NL: nextNL}
+	l := &limitedReaderNL{R: r, N: n}
+	if nextNL {
+		l.Delim = '\n'
+	}
+	return l
+}
+
+// NewLimitReaderErr is like limitReaderNL, but the returned reader signals
+// hitting its cap with err instead of io.EOF. This lets a caller driving the
+// reader with io.Copy distinguish "the source ended on its own" from "we
+// truncated it at n bytes (or the first newline past n, if nextNL)".
+func NewLimitReaderErr(r io.Reader, n int64, nextNL bool, err error) io.Reader {
+	l := &limitedReaderNL{R: r, N: n, Err: err}
+	if nextNL {
+		l.Delim = '\n'
+	}
+	return l
+}
+
+// NewDelimitedReader is like NewLimitReaderErr, but the terminator sought
+// past n bytes is a caller-chosen delimiter rather than always '\n': delim
+// if delimFunc is nil, otherwise whichever byte delimFunc matches first.
+// keepDelim controls whether that terminating byte is included in the last
+// Read's returned count or dropped, so this can back both line-oriented
+// parsing (drop the delimiter) and length-delimited binary framing (keep
+// it) without a second buffer copy either way.
+func NewDelimitedReader(r io.Reader, n int64, delim byte, delimFunc func(byte) bool, keepDelim bool, err error) io.Reader {
+	return &limitedReaderNL{R: r, N: n, Delim: delim, DelimFunc: delimFunc, KeepDelim: keepDelim, Err: err}
 }
 
 // A limitedReaderNL reads from R but limits the amount of
 // data returned to just N bytes. Each call to Read
 // updates N to reflect the new amount remaining.
-// Read returns EOF when N <= 0 or when the underlying R returns EOF.
+// Read returns Err (or io.EOF, if Err is nil) when N <= 0 or the delimiter
+// is reached; it returns the underlying R's own error unchanged when R runs
+// out first.
 type limitedReaderNL struct {
-	R  io.Reader // underlying reader
-	N  int64     // max bytes remaining
-	NL bool      // wait for newline.
+	R io.Reader // underlying reader
+	N int64     // max bytes remaining
+
+	// Delim, if nonzero, is the byte that terminates the read early once N
+	// has been reached, the same way '\n' did before this became
+	// configurable. DelimFunc, if non-nil, takes priority over Delim and
+	// matches any byte it reports true for (e.g. any control byte).
+	Delim     byte
+	DelimFunc func(byte) bool
+	// KeepDelim includes the matched delimiter byte in the final Read's
+	// count instead of dropping it, e.g. for length-delimited framing
+	// where the delimiter itself is part of the payload.
+	KeepDelim bool
+
+	Err error // error returned once the cap is reached, defaults to io.EOF
+}
+
+// hasDelim reports whether Read should keep reading past N looking for a
+// delimiter, instead of stopping exactly at N.
+func (l *limitedReaderNL) hasDelim() bool {
+	return l.DelimFunc != nil || l.Delim != 0
+}
+
+// isDelim reports whether b is the configured delimiter.
+func (l *limitedReaderNL) isDelim(b byte) bool {
+	if l.DelimFunc != nil {
+		return l.DelimFunc(b)
+	}
+	return b == l.Delim
+}
+
+func (l *limitedReaderNL) capErr() error {
+	if l.Err != nil {
+		return l.Err
+	}
+	return io.EOF
 }
 
 func (l *limitedReaderNL) Read(p []byte) (n int, err error) {
-	if l.N <= 0 && !l.NL {
-		return 0, io.EOF
+	wantDelim := l.hasDelim()
+	if l.N <= 0 && !wantDelim {
+		return 0, l.capErr()
 	}
-	if !l.NL && int64(len(p)) > l.N {
+	if !wantDelim && int64(len(p)) > l.N {
 		p = p[0:l.N]
 	}
 	n, err = l.R.Read(p)
-	if l.NL && int64(n) > l.N {
+	if wantDelim && int64(n) > l.N {
 		end := int(l.N)
 		for end < n {
-			if p[end] == '\n' {
-				err = io.EOF
-				l.NL = false
+			if l.isDelim(p[end]) {
+				err = l.capErr()
+				l.Delim = 0
+				l.DelimFunc = nil
+				if l.KeepDelim {
+					end++
+				}
 				n = end
 				break
 			}
@@ -922,3 +1281,100 @@ func (l *limitedReaderNL) Read(p []byte) (n int, err error) {
 	}
 	return
 }
+
+// WriteTo implements io.WriterTo, so io.Copy(dst, lr) can skip its own
+// generic buffered loop. With a delimiter configured we can't tell we've
+// crossed it without inspecting bytes as they're produced, so that case
+// still goes through the byte-by-byte Read path below. Otherwise, if R
+// itself implements io.WriterTo, we hand it a limitWriter wrapping dst: that
+// keeps dst's own fast path reachable (e.g. a *os.File destination's
+// ReaderFrom, for a sendfile(2) transfer) instead of forcing everything
+// through Write.
+func (l *limitedReaderNL) WriteTo(w io.Writer) (written int64, err error) {
+	if !l.hasDelim() {
+		if wt, ok := l.R.(io.WriterTo); ok {
+			lw := &limitWriter{W: w, N: l.N}
+			n, err := wt.WriteTo(lw)
+			l.N -= n
+			if errors.Is(err, errLimitWriterFull) {
+				err = nil
+			}
+			if err == nil && l.N <= 0 {
+				err = l.capErr()
+			}
+			return n, err
+		}
+	}
+	buf := make([]byte, 32<<10)
+	for {
+		nr, er := l.Read(buf)
+		if nr > 0 {
+			nw, ew := w.Write(buf[:nr])
+			written += int64(nw)
+			if ew != nil {
+				return written, ew
+			}
+			if nr != nw {
+				return written, io.ErrShortWrite
+			}
+		}
+		if er != nil {
+			if er == io.EOF {
+				er = nil
+			}
+			return written, er
+		}
+	}
+}
+
+// errLimitWriterFull signals that a limitWriter has reached its cap; it
+// never escapes to a limitWriter's own caller, only to its WriterTo/ReadFrom
+// source, which is expected to stop and return it like any other write error.
+var errLimitWriterFull = errors.New("mz: limitWriter cap reached")
+
+// limitWriter bounds writes to at most N bytes, so a WriterTo/ReaderFrom fast
+// path writing directly into it stops cleanly at the cap instead of copying
+// the source's entire remaining data into W.
+type limitWriter struct {
+	W io.Writer
+	N int64
+}
+
+func (lw *limitWriter) Write(p []byte) (int, error) {
+	if lw.N <= 0 {
+		return 0, errLimitWriterFull
+	}
+	if int64(len(p)) > lw.N {
+		p = p[:lw.N]
+	}
+	n, err := lw.W.Write(p)
+	lw.N -= int64(n)
+	if err == nil && lw.N <= 0 {
+		err = errLimitWriterFull
+	}
+	return n, err
+}
+
+// ReadFrom lets a bounded destination still degrade to W's own ReaderFrom
+// (e.g. *os.File, for a sendfile(2) fast path) when it has one, instead of
+// forcing the transfer through Write one buffer at a time.
+func (lw *limitWriter) ReadFrom(r io.Reader) (int64, error) {
+	if lw.N <= 0 {
+		return 0, errLimitWriterFull
+	}
+	rf, ok := lw.W.(io.ReaderFrom)
+	if !ok {
+		return io.Copy(writerOnly{lw}, r)
+	}
+	n, err := rf.ReadFrom(io.LimitReader(r, lw.N))
+	lw.N -= n
+	if err == nil && lw.N <= 0 {
+		err = errLimitWriterFull
+	}
+	return n, err
+}
+
+// writerOnly hides any WriterTo/ReaderFrom method of the wrapped value, so
+// io.Copy always falls back to its own buffered Read/Write loop instead of
+// re-entering lw.ReadFrom.
+type writerOnly struct{ io.Writer }
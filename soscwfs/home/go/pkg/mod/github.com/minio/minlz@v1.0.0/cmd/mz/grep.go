@@ -0,0 +1,112 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"regexp"
+)
+
+// errGrepLineTooLong is returned by grepReader when a line exceeds maxLine
+// bytes without a terminating '\n'.
+var errGrepLineTooLong = errors.New("mz: line exceeds --grep-max-line without a newline")
+
+// grepOptions configures --grep filtering of a decoded stream, threaded
+// through decompressFile. A nil *grepOptions disables filtering: decoded
+// is passed straight through to --limit/out untouched.
+type grepOptions struct {
+	pattern *regexp.Regexp
+	invert  bool // --grep-v
+	before  int  // --before
+	after   int  // --after
+	maxLine int  // --grep-max-line
+}
+
+// grepReader wraps r, emitting only lines matching opts.pattern (or, if
+// opts.invert, lines that don't), along with opts.before lines preceding and
+// opts.after lines following each match. It sits between the decoded stream
+// and --limit, so a subsequent limitedReaderNL truncates the filtered output
+// rather than the raw decoded bytes, and naturally short-circuits reading
+// from grepReader once that limit is satisfied.
+type grepReader struct {
+	src  *bufio.Reader
+	opts *grepOptions
+
+	pending   []byte
+	ring      [][]byte
+	afterLeft int
+	err       error
+}
+
+func newGrepReader(r io.Reader, opts *grepOptions) *grepReader {
+	maxLine := opts.maxLine
+	if maxLine <= 0 {
+		maxLine = 1 << 20
+	}
+	return &grepReader{src: bufio.NewReaderSize(r, maxLine), opts: opts}
+}
+
+// readLine returns the next line, including its trailing '\n' if present.
+// It returns errGrepLineTooLong instead of growing past the reader's buffer.
+func (g *grepReader) readLine() ([]byte, error) {
+	var line []byte
+	for {
+		chunk, err := g.src.ReadSlice('\n')
+		line = append(line, chunk...)
+		if err == bufio.ErrBufferFull {
+			return line, errGrepLineTooLong
+		}
+		return line, err
+	}
+}
+
+func (g *grepReader) Read(p []byte) (int, error) {
+	for len(g.pending) == 0 {
+		if g.err != nil {
+			return 0, g.err
+		}
+		line, err := g.readLine()
+		if len(line) == 0 {
+			g.err = err
+			continue
+		}
+		matched := g.opts.pattern.Match(line) != g.opts.invert
+		switch {
+		case matched:
+			for _, b := range g.ring {
+				g.pending = append(g.pending, b...)
+			}
+			g.ring = g.ring[:0]
+			g.pending = append(g.pending, line...)
+			g.afterLeft = g.opts.after
+		case g.afterLeft > 0:
+			g.pending = append(g.pending, line...)
+			g.afterLeft--
+		case g.opts.before > 0:
+			g.ring = append(g.ring, append([]byte(nil), line...))
+			if len(g.ring) > g.opts.before {
+				g.ring = g.ring[1:]
+			}
+		}
+		if err != nil {
+			g.err = err
+		}
+	}
+	n := copy(p, g.pending)
+	g.pending = g.pending[n:]
+	return n, nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/minio/minlz"
+)
+
+// sidecarExt is appended to a compressed file's name to name its standalone
+// TOC, written and checked by the `index build`/`index verify` subcommands
+// for streams that weren't written with an embedded index footer (see
+// minlz.SeekableWriter) to begin with.
+const sidecarExt = ".mz.idx"
+
+func mainIndex(args []string) {
+	if len(args) == 0 {
+		exitErr(fmt.Errorf("usage: %s index build|verify [options] <input>", os.Args[0]))
+	}
+	sub, args := args[0], args[1:]
+	switch sub {
+	case "build":
+		mainIndexBuild(args)
+	case "verify":
+		mainIndexVerify(args)
+	default:
+		exitErr(fmt.Errorf("unknown index subcommand %q, want build or verify", sub))
+	}
+}
+
+func mainIndexBuild(args []string) {
+	fs := flag.NewFlagSet("index build", flag.ExitOnError)
+	out := fs.String("o", "", "Write the sidecar to this path instead of <input>"+sidecarExt)
+	fs.Usage = func() {
+		w := fs.Output()
+		fmt.Fprintln(w, "Builds a TOC sidecar file next to a compressed input, for random-access decompression without an embedded index footer.")
+		fs.PrintDefaults()
+		fmt.Fprintf(w, "\nUsage: %v index build [options] <input>\n", os.Args[0])
+	}
+	fs.Parse(args)
+	args = fs.Args()
+	if len(args) != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	filename := args[0]
+	sidecar := *out
+	if sidecar == "" {
+		sidecar = filename + sidecarExt
+	}
+
+	file, err := os.Open(filename)
+	exitErr(err)
+	defer file.Close()
+	st, err := file.Stat()
+	exitErr(err)
+
+	idx, err := minlz.LoadIndex(file, st.Size())
+	if err != nil {
+		idx, err = minlz.IndexStream(file, st.Size())
+		exitErr(err)
+	}
+
+	dst, err := os.Create(sidecar)
+	exitErr(err)
+	defer dst.Close()
+	_, err = dst.Write(idx.AppendTo(nil))
+	exitErr(err)
+	fmt.Printf("Wrote %s: %d block(s), %d bytes uncompressed\n", sidecar, len(idx.Offsets), idx.TotalUncompressed)
+}
+
+func mainIndexVerify(args []string) {
+	fs := flag.NewFlagSet("index verify", flag.ExitOnError)
+	sidecarFlag := fs.String("idx", "", "Read the sidecar from this path instead of <input>"+sidecarExt)
+	fs.Usage = func() {
+		w := fs.Output()
+		fmt.Fprintln(w, "Checks a TOC sidecar against its compressed input: every indexed block must decode and pass its checksum (and strong digest, if any).")
+		fs.PrintDefaults()
+		fmt.Fprintf(w, "\nUsage: %v index verify [options] <input>\n", os.Args[0])
+	}
+	fs.Parse(args)
+	args = fs.Args()
+	if len(args) != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	filename := args[0]
+	sidecar := *sidecarFlag
+	if sidecar == "" {
+		sidecar = filename + sidecarExt
+	}
+
+	sf, err := os.Open(sidecar)
+	exitErr(err)
+	defer sf.Close()
+	sst, err := sf.Stat()
+	exitErr(err)
+	idx, err := minlz.LoadIndex(sf, sst.Size())
+	exitErr(err)
+
+	file, err := os.Open(filename)
+	exitErr(err)
+	defer file.Close()
+	st, err := file.Stat()
+	exitErr(err)
+	if idx.TotalCompressed >= 0 && idx.TotalCompressed != st.Size() {
+		exitErr(fmt.Errorf("%s: sidecar expects %d compressed bytes, input is %d", filename, idx.TotalCompressed, st.Size()))
+	}
+
+	sr, err := minlz.NewSeekableReaderAt(file, st.Size(), idx)
+	exitErr(err)
+
+	buf := make([]byte, 4<<20)
+	var off int64
+	for off < idx.TotalUncompressed {
+		n, err := sr.ReadAt(buf, off)
+		if err != nil && err != io.EOF {
+			exitErr(fmt.Errorf("%s: block covering offset %d: %w", filename, off, err))
+		}
+		off += int64(n)
+		if n == 0 {
+			break
+		}
+	}
+	if off != idx.TotalUncompressed {
+		exitErr(fmt.Errorf("%s: decoded %d bytes, sidecar expects %d", filename, off, idx.TotalUncompressed))
+	}
+	fmt.Printf("OK: %s matches %s (%d block(s), %d bytes uncompressed)\n", sidecar, filename, len(idx.Offsets), idx.TotalUncompressed)
+}
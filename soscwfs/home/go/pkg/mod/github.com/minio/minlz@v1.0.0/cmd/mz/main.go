@@ -42,6 +42,7 @@ const (
 	s2Ext         = ".s2"
 	snappyExt     = ".sz" // https://github.com/google/snappy/blob/main/framing_format.txt#L34
 	minlzBlockExt = ".mzb"
+	zipExt        = ".zip"
 )
 
 var debugErrs bool
@@ -62,7 +63,11 @@ func main() {
 		fmt.Fprintf(w, "Usage:\nCompress:     %s c [options] <input>\n", os.Args[0])
 		fmt.Fprintf(w, "Decompress:   %s d [options] <input>\n", os.Args[0])
 		fmt.Fprintf(w, " (cat)    :   %s cat [options] <input>\n", os.Args[0])
-		fmt.Fprintf(w, " (tail)   :   %s tail [options] <input>\n\n", os.Args[0])
+		fmt.Fprintf(w, " (tail)   :   %s tail [options] <input>\n", os.Args[0])
+		fmt.Fprintf(w, " (index)  :   %s index build|verify [options] <input>\n", os.Args[0])
+		fmt.Fprintf(w, " (train)  :   %s train [options] <input...>\n", os.Args[0])
+		fmt.Fprintf(w, " (list)   :   %s list <input>\n", os.Args[0])
+		fmt.Fprintf(w, " (extract):   %s extract [options] <input> <path-glob>\n\n", os.Args[0])
 		fmt.Fprintf(w, "Without options 'c' and 'd' can be omitted. Extension decides if decompressing.\n")
 		fmt.Fprintf(w, "Compress file:    %s file.txt\n", os.Args[0])
 		fmt.Fprintf(w, "Compress stdin:   %s -\n", os.Args[0])
@@ -107,6 +112,14 @@ func main() {
 		mainCompress(flag.Args()[1:])
 	case "d", "decompress", "tail", "cat":
 		mainDecompress(flag.Args()[1:], flag.Arg(0) == "cat", flag.Arg(0) == "tail")
+	case "index":
+		mainIndex(flag.Args()[1:])
+	case "train":
+		mainTrain(flag.Args()[1:])
+	case "list":
+		mainList(flag.Args()[1:])
+	case "extract":
+		mainExtract(flag.Args()[1:])
 	default:
 		if len(flag.Args()) > 0 {
 			cmp := strings.ToLower(flag.Arg(0))
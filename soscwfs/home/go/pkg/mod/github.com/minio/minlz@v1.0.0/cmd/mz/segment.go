@@ -0,0 +1,139 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"hash"
+	"io"
+)
+
+// errSegmentShort is returned by Next and Close when the underlying stream
+// ran out before the current segment's declared length was fully read.
+var errSegmentShort = errors.New("mz: segment ended before its declared length")
+
+// errSegmentChecksum is returned by Next when the previous segment's bytes
+// didn't match the checksum given to SetChecksum.
+var errSegmentChecksum = errors.New("mz: segment checksum mismatch")
+
+// SegmentLenFunc lazily produces the next segment length for a
+// SegmentReader. It returns io.EOF once there are no more segments.
+type SegmentLenFunc func() (int64, error)
+
+// NewSegmentReader returns a SegmentReader walking r as a sequence of
+// byte-limited segments, each produced on demand by lens, similar to how
+// multipart or archive/tar walk from one bounded body to the next over a
+// single underlying stream.
+func NewSegmentReader(r io.Reader, lens SegmentLenFunc) *SegmentReader {
+	return &SegmentReader{r: r, lens: lens}
+}
+
+// NewFixedSegmentReader is NewSegmentReader for a pre-known slice of lengths.
+func NewFixedSegmentReader(r io.Reader, lens []int64) *SegmentReader {
+	i := 0
+	return NewSegmentReader(r, func() (int64, error) {
+		if i >= len(lens) {
+			return 0, io.EOF
+		}
+		n := lens[i]
+		i++
+		return n, nil
+	})
+}
+
+// SegmentReader turns a single underlying stream into a sequence of
+// byte-limited sub-readers. Each segment must be read to completion (Next
+// drains any leftovers itself) before the next one becomes available, so
+// segment boundaries always land at the right byte offset in R regardless
+// of how much of a segment the caller actually consumed.
+type SegmentReader struct {
+	r    io.Reader
+	lens SegmentLenFunc
+
+	cur     *limitedReaderNL
+	hasher  hash.Hash
+	wantSum []byte
+}
+
+// Next finishes the current segment -- draining any bytes the caller didn't
+// read and, if SetChecksum was called, verifying its checksum -- then opens
+// the next one. It returns io.EOF once lens reports no more segments, or
+// errSegmentShort if R ran out before a segment's declared length was
+// reached.
+func (s *SegmentReader) Next() (io.Reader, error) {
+	if err := s.finish(); err != nil {
+		return nil, err
+	}
+	n, err := s.lens()
+	if err != nil {
+		s.cur = nil
+		return nil, err
+	}
+	s.cur = &limitedReaderNL{R: s.r, N: n}
+	s.hasher = nil
+	s.wantSum = nil
+	return s.cur, nil
+}
+
+// finish drains and checksums whatever segment is current, leaving the
+// underlying stream positioned at the start of the next segment.
+//
+// cur.Read returns io.EOF once N reaches zero, and R's own io.EOF if R runs
+// dry first; io.Copy treats both as a clean end, so cur.N > 0 afterwards is
+// the one case that means R ran out early.
+func (s *SegmentReader) finish() error {
+	if s.cur == nil {
+		return nil
+	}
+	var w io.Writer = io.Discard
+	if s.hasher != nil {
+		w = s.hasher
+	}
+	if _, err := io.Copy(w, s.cur); err != nil && !errors.Is(err, errSegmentShort) {
+		return err
+	}
+	if s.cur.N > 0 {
+		return errSegmentShort
+	}
+	if s.wantSum != nil && !bytes.Equal(s.hasher.Sum(nil), s.wantSum) {
+		return errSegmentChecksum
+	}
+	return nil
+}
+
+// SetChecksum arranges for bytes read from the segment most recently
+// returned by Next to be hashed with h as they flow through, mirroring the
+// checksumReader pattern archive/zip uses to verify CRC-32 while decoding.
+// The hash is checked against want the next time Next (or Close) is called;
+// h should not have been written to yet. Call this right after Next, before
+// reading the segment.
+func (s *SegmentReader) SetChecksum(h hash.Hash, want []byte) {
+	if s.cur == nil {
+		return
+	}
+	s.hasher = h
+	s.wantSum = want
+}
+
+// Close finishes the current segment (see Next) without opening another
+// one. It's a no-op if no segment is open.
+func (s *SegmentReader) Close() error {
+	err := s.finish()
+	s.cur = nil
+	s.hasher = nil
+	s.wantSum = nil
+	return err
+}
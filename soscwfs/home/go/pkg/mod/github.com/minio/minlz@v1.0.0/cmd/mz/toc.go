@@ -0,0 +1,164 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+
+	"github.com/minio/minlz"
+	"github.com/minio/minlz/mzarchive"
+	"github.com/minio/minlz/mztar"
+)
+
+// openSeekable is openFile(name, true), asserted to also support Seek: every
+// concrete type it can return (*os.File, *shttp.Reader) does, which is what
+// mztar.OpenTOCReader and minlz.LoadIndex need for random access.
+func openSeekable(name string) (rs io.ReadSeeker, size int64) {
+	rc, size, _ := openFile(name, true)
+	rs, ok := rc.(io.ReadSeeker)
+	if !ok {
+		exitErr(fmt.Errorf("%s: does not support seeking", name))
+	}
+	return rs, size
+}
+
+func mainList(args []string) {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	fs.Usage = func() {
+		w := fs.Output()
+		fmt.Fprintln(w, "Lists the entries of a tarball compressed with a table of contents (see mztar.NewTOCWriter)")
+		fmt.Fprintln(w, "or an -archive mz container (see mzarchive.Writer), or, for a plain .mz input, the block")
+		fmt.Fprintln(w, "boundaries recorded in its index.")
+		fs.PrintDefaults()
+		fmt.Fprintf(w, "\nUsage: %v list <input>\n", os.Args[0])
+	}
+	fs.Parse(args)
+	args = fs.Args()
+	if len(args) != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	filename := args[0]
+
+	rs, size := openSeekable(filename)
+	if closer, ok := rs.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	if toc, err := mztar.OpenTOCReader(rs, size); err == nil {
+		for _, e := range toc.Entries() {
+			fmt.Printf("%10d  %s  %s\n", e.Size, e.ModTime.Format("2006-01-02 15:04:05"), e.Name)
+		}
+		return
+	}
+
+	if ra, ok := rs.(io.ReaderAt); ok {
+		if ar, err := mzarchive.OpenReader(ra, size); err == nil {
+			for _, e := range ar.Entries() {
+				fmt.Printf("%10d  %s  %s  %s\n", e.UncompressedSize, e.ModTime.Format("2006-01-02 15:04:05"), e.Mode, e.Name)
+			}
+			return
+		}
+	}
+
+	ra, ok := rs.(io.ReaderAt)
+	if !ok {
+		exitErr(fmt.Errorf("%s: carries no table of contents, and this input type does not support the random access an index listing needs", filename))
+	}
+	idx, err := minlz.LoadIndex(ra, size)
+	exitErr(err)
+	for _, b := range idx.Offsets {
+		fmt.Printf("uncompressed offset %10d  compressed offset %10d\n", b.UncompressedOffset, b.CompressedOffset)
+	}
+	fmt.Printf("total: %d bytes uncompressed, %d blocks\n", idx.TotalUncompressed, len(idx.Offsets))
+}
+
+func mainExtract(args []string) {
+	fs := flag.NewFlagSet("extract", flag.ExitOnError)
+	outDir := fs.String("o", ".", "Write extracted files under this directory")
+	cpu := fs.Int("cpu", runtime.GOMAXPROCS(0), "Maximum number of threads to use decoding each -archive mz entry")
+	fs.Usage = func() {
+		w := fs.Output()
+		fmt.Fprintln(w, "Extracts entries matching path-glob from a tarball compressed with a table of")
+		fmt.Fprintln(w, "contents (see mztar.NewTOCWriter) or an -archive mz container (see mzarchive.Writer),")
+		fmt.Fprintln(w, "reading only the block(s)/entry each one covers. An -archive mz extraction also")
+		fmt.Fprintln(w, "restores each entry's mode and modification time.")
+		fs.PrintDefaults()
+		fmt.Fprintf(w, "\nUsage: %v extract [options] <input> <path-glob>\n", os.Args[0])
+	}
+	fs.Parse(args)
+	args = fs.Args()
+	if len(args) != 2 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	filename, pattern := args[0], args[1]
+
+	rs, size := openSeekable(filename)
+	if closer, ok := rs.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	if toc, err := mztar.OpenTOCReader(rs, size); err == nil {
+		extractTOC(toc, pattern, *outDir, filename)
+		return
+	}
+
+	ra, ok := rs.(io.ReaderAt)
+	if !ok {
+		exitErr(fmt.Errorf("%s: does not support the random access extraction needs", filename))
+	}
+	ar, err := mzarchive.OpenReader(ra, size)
+	exitErr(err)
+	if matched := extractArchive(ar, pattern, *outDir, *cpu); matched == 0 {
+		exitErr(fmt.Errorf("%s: no entry matches %q", filename, pattern))
+	}
+}
+
+// extractTOC restores every mztar TOC entry matching pattern under outDir.
+func extractTOC(toc *mztar.TOCReader, pattern, outDir, filename string) {
+	var matched int
+	for _, e := range toc.Entries() {
+		ok, err := path.Match(pattern, e.Name)
+		exitErr(err)
+		if !ok {
+			continue
+		}
+		matched++
+
+		dst := filepath.Join(outDir, filepath.FromSlash(e.Name))
+		exitErr(os.MkdirAll(filepath.Dir(dst), 0o755))
+
+		rc, err := toc.Open(e.Name)
+		exitErr(err)
+		f, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(e.Mode))
+		exitErr(err)
+		_, err = io.Copy(f, rc)
+		rc.Close()
+		cerr := f.Close()
+		exitErr(err)
+		exitErr(cerr)
+		fmt.Println(dst)
+	}
+	if matched == 0 {
+		exitErr(fmt.Errorf("%s: no entry matches %q", filename, pattern))
+	}
+}
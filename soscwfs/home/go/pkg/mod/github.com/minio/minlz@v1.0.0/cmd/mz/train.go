@@ -0,0 +1,120 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/minio/minlz"
+	"github.com/minio/minlz/cmd/internal/filepathx"
+)
+
+func mainTrain(args []string) {
+	fs := flag.NewFlagSet("train", flag.ExitOnError)
+	var (
+		out    = fs.String("o", "dictionary.bin", "Write the trained dictionary to this file")
+		size   = fs.String("size", "64K", "Target dictionary size. Examples: 16K, 64K, 256K")
+		method = fs.String("method", "suffix", "Training method: 'suffix' (see minlz.BuildDict) or 'freq' (see minlz.TrainDict)")
+		safe   = fs.Bool("safe", false, "Do not overwrite an existing output file")
+		help   = fs.Bool("help", false, "Display help")
+	)
+	fs.Usage = func() {
+		w := fs.Output()
+		fmt.Fprintln(w, "Trains a dictionary from many small sample files, for use with -dict on 'c'/'d'.")
+		fmt.Fprintln(w, "Substrings are scored by how often they recur across the samples, and the most")
+		fmt.Fprintln(w, "frequent ones are concatenated until the target size is reached.")
+		fmt.Fprintln(w, "")
+		fmt.Fprintln(w, "Pass sample files (wildcards accepted) as input, or '-' to read newline-delimited")
+		fmt.Fprintln(w, "records from stdin, one sample per line.")
+		fs.PrintDefaults()
+		fmt.Fprintf(w, "\nUsage: %v train [options] <input...>\n", os.Args[0])
+	}
+	fs.Parse(args)
+	args = fs.Args()
+	if len(args) == 0 || *help {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	sz, err := toSize(*size)
+	exitErr(err)
+
+	var samples [][]byte
+	if len(args) == 1 && args[0] == "-" {
+		sc := bufio.NewScanner(os.Stdin)
+		sc.Buffer(make([]byte, 64<<10), 1<<20)
+		for sc.Scan() {
+			line := sc.Bytes()
+			if len(line) == 0 {
+				continue
+			}
+			sample := make([]byte, len(line))
+			copy(sample, line)
+			samples = append(samples, sample)
+		}
+		exitErr(sc.Err())
+	} else {
+		var files []string
+		for _, pattern := range args {
+			found, err := filepathx.Glob(pattern)
+			exitErr(err)
+			if len(found) == 0 {
+				exitErr(fmt.Errorf("unable to find file %v", pattern))
+			}
+			files = append(files, found...)
+		}
+		for _, f := range files {
+			b, err := os.ReadFile(f)
+			exitErr(err)
+			samples = append(samples, b)
+		}
+	}
+	if len(samples) == 0 {
+		exitErr(errors.New("no training samples found"))
+	}
+
+	var dict *minlz.Dict
+	switch *method {
+	case "suffix":
+		dict = minlz.BuildDict(samples, int(sz))
+	case "freq":
+		dict = minlz.TrainDict(samples, int(sz))
+	default:
+		exitErr(fmt.Errorf("unknown -method %q, want 'suffix' or 'freq'", *method))
+	}
+
+	if *safe {
+		if _, err := os.Stat(*out); !os.IsNotExist(err) {
+			exitErr(errors.New("destination file exists"))
+		}
+	}
+	blob := dict.AppendTo(nil)
+	exitErr(os.WriteFile(*out, blob, 0o644))
+	fmt.Printf("Trained %d-byte dictionary from %d sample(s) -> %s\n", len(dict.Bytes()), len(samples), *out)
+}
+
+// loadDictFile reads and parses a dictionary file written by `train`, for
+// the -dict flag shared by the compress and decompress commands.
+func loadDictFile(name string) *minlz.Dict {
+	b, err := os.ReadFile(name)
+	exitErr(err)
+	d, err := minlz.LoadDict(b)
+	exitErr(err)
+	return d
+}
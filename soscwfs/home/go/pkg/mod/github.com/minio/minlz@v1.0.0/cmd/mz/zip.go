@@ -0,0 +1,189 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+
+	"github.com/klauspost/compress/zip"
+	"github.com/minio/minlz"
+	"github.com/minio/minlz/minlzzip"
+)
+
+// decompressZip handles a .zip input to mainDecompress: it lists members
+// whose compression method is minlzzip.Method, selects the ones matching
+// glob, and decodes each to dstDir (or stdout with -c). --offset/--tail
+// require glob to narrow the archive down to a single member, since they
+// seek a position in one decoded stream rather than a set of them.
+func decompressZip(quiet *bool, filename, dstDir, glob string, stdout, safe, verify, remove *bool, cpu *int, tailBytes, offset, limitBytes int64, tailNextNL, limitNextNL bool) {
+	minlzzip.Register(minlz.LevelBalanced)
+
+	f, err := os.Open(filename)
+	exitErr(err)
+	defer f.Close()
+	st, err := f.Stat()
+	exitErr(err)
+	zr, err := zip.NewReader(f, st.Size())
+	exitErr(err)
+
+	var members []*zip.File
+	for _, zf := range zr.File {
+		if zf.Method != minlzzip.Method {
+			continue
+		}
+		ok := glob == "*"
+		if !ok {
+			var err error
+			ok, err = path.Match(glob, zf.Name)
+			exitErr(err)
+		}
+		if ok {
+			members = append(members, zf)
+		}
+	}
+	if len(members) == 0 {
+		exitErr(fmt.Errorf("%s: no minlz member matches %q", filename, glob))
+	}
+	if (tailBytes > 0 || offset > 0) && len(members) != 1 {
+		exitErr(fmt.Errorf("%s: -member %q matches %d entries; --offset/--tail require exactly one", filename, glob, len(members)))
+	}
+
+	workers := *cpu
+	if workers < 1 {
+		workers = 1
+	}
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	for _, zf := range members {
+		zf := zf
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			err := decompressZipMember(f, zf, quiet, dstDir, stdout, safe, verify, tailBytes, offset, limitBytes, tailNextNL, limitNextNL)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("%s: %w", zf.Name, err)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	exitErr(firstErr)
+
+	if *remove && !*verify {
+		f.Close()
+		if !*quiet {
+			fmt.Println("Removing", filename)
+		}
+		exitErr(os.Remove(filename))
+	}
+}
+
+// decompressZipMember decodes a single zip member to dstDir (or stdout/
+// io.Discard, per stdout/verify), honoring --offset/--tail/--limit by
+// seeking the member's own compressed range with minlz.Reader.ReadSeeker
+// instead of decoding it from a plain zf.Open() stream.
+func decompressZipMember(ra io.ReaderAt, zf *zip.File, quiet *bool, dstDir string, stdout, safe, verify *bool, tailBytes, offset, limitBytes int64, tailNextNL, limitNextNL bool) (err error) {
+	if !*quiet {
+		fmt.Println("Decompressing", zf.Name)
+	}
+
+	var out io.Writer
+	var dstFile *os.File
+	switch {
+	case *verify:
+		out = io.Discard
+	case *stdout:
+		out = os.Stdout
+	default:
+		dst := filepath.Join(dstDir, filepath.FromSlash(zf.Name))
+		if err := os.MkdirAll(filepath.Dir(dst), 0o777); err != nil {
+			return err
+		}
+		if *safe {
+			if _, err := os.Stat(dst); !os.IsNotExist(err) {
+				return fmt.Errorf("destination file exists: %s", dst)
+			}
+		}
+		dstFile, err = os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o666)
+		if err != nil {
+			return err
+		}
+		defer dstFile.Close()
+		out = dstFile
+	}
+
+	var decoded io.Reader
+	if tailBytes > 0 || offset > 0 {
+		dataOff, err := zf.DataOffset()
+		if err != nil {
+			return err
+		}
+		sec := io.NewSectionReader(ra, dataOff, int64(zf.CompressedSize64))
+		r := minlz.NewReader(sec)
+		rs, err := r.ReadSeeker(nil)
+		if err != nil {
+			return err
+		}
+	retry:
+		if tailBytes > 0 {
+			_, err = rs.Seek(-tailBytes, io.SeekEnd)
+		} else {
+			_, err = rs.Seek(offset, io.SeekStart)
+		}
+		if err != nil {
+			return err
+		}
+		if tailNextNL {
+			for {
+				b, err := r.ReadByte()
+				if err == io.EOF {
+					tailNextNL = false
+					goto retry
+				}
+				if err != nil {
+					return err
+				}
+				if b == '\n' {
+					break
+				}
+			}
+		}
+		decoded = r
+	} else {
+		rc, err := zf.Open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		decoded = rc
+	}
+	if limitBytes > 0 {
+		decoded = limitReaderNL(decoded, limitBytes, limitNextNL)
+	}
+	_, err = io.Copy(out, decoded)
+	return err
+}
@@ -0,0 +1,244 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+import "encoding/binary"
+
+// ConcatBlocks concatenates the decoded content of blocks (each a complete
+// block as produced by Encode, at any level, including zero-length and
+// uncompressed-stored blocks) into a single encoded block appended to dst,
+// without a decompress/recompress round trip. It rewrites each block's tag
+// stream onto a shared repeat-offset register instead of splicing bytes
+// verbatim, since the register each block's own encoder assumed at its
+// first tag is not generally valid once more than one block is involved.
+func ConcatBlocks(dst []byte, blocks ...[]byte) ([]byte, error) {
+	total := 0
+	for _, b := range blocks {
+		n, err := DecodedLen(b)
+		if err != nil {
+			return nil, err
+		}
+		total += n
+		if total > MaxBlockSize {
+			return nil, ErrTooLarge
+		}
+	}
+
+	dst = append(dst, 0)
+	if total == 0 {
+		return dst, nil
+	}
+	dst = binary.AppendUvarint(dst, uint64(total))
+
+	repeat := 1
+	for _, b := range blocks {
+		isMLZ, lits, body, dLen, err := isMinLZ(b)
+		if !isMLZ || err != nil {
+			return nil, ErrCorrupt
+		}
+		if dLen == 0 {
+			continue
+		}
+		if lits {
+			dst = emitLiteral(dst, body)
+			continue
+		}
+		if dst, err = appendRewrittenTags(dst, body, &repeat); err != nil {
+			return nil, err
+		}
+	}
+	return dst, nil
+}
+
+// appendRewrittenTags parses the tag stream of a single encoded (non-raw,
+// non-empty) block, parsed the same way decodeWithBase parses it, and re-emits an
+// equivalent tag stream onto dst using *repeat as the running repeat-offset
+// register, updating it as copies are emitted.
+func appendRewrittenTags(dst, src []byte, repeat *int) ([]byte, error) {
+	offset := uint32(1)
+
+	readOne := func() (v uint32, ok bool) {
+		if len(src) >= 1 {
+			v, src = uint32(src[0]), src[1:]
+			return v, true
+		}
+		return 0, false
+	}
+	readTwo := func() (v uint32, ok bool) {
+		if len(src) >= 2 {
+			v, src = uint32(src[0])|uint32(src[1])<<8, src[2:]
+			return v, true
+		}
+		return 0, false
+	}
+	readThree := func() (v uint32, ok bool) {
+		if len(src) >= 3 {
+			v, src = uint32(src[0])|uint32(src[1])<<8|uint32(src[2])<<16, src[3:]
+			return v, true
+		}
+		return 0, false
+	}
+	readN := func(n uint32) (v []byte, ok bool) {
+		if uint32(len(src)) >= n {
+			v, src = src[:n], src[n:]
+			return v, true
+		}
+		return nil, false
+	}
+
+	emit := func(length int) {
+		if uint32(offset) == uint32(*repeat) {
+			dst = emitRepeat(dst, length)
+		} else {
+			dst = emitCopy(dst, int(offset), length)
+		}
+		*repeat = int(offset)
+	}
+
+	for len(src) > 0 {
+		v, ok := readOne()
+		if !ok {
+			return nil, ErrCorrupt
+		}
+		tag := v & 3
+		value := v >> 2
+		var length uint32
+
+		switch tag {
+		case 0:
+			isRepeat := value&1 != 0
+			value >>= 1
+			switch {
+			case value < 29:
+				length = value + 1
+			case value == 29:
+				if length, ok = readOne(); !ok {
+					return nil, ErrCorrupt
+				}
+				length += 30
+			case value == 30:
+				if length, ok = readTwo(); !ok {
+					return nil, ErrCorrupt
+				}
+				length += 30
+			default:
+				if length, ok = readThree(); !ok {
+					return nil, ErrCorrupt
+				}
+				length += 30
+			}
+			if isRepeat {
+				emit(int(length))
+				continue
+			}
+			input, ok := readN(length)
+			if !ok {
+				return nil, ErrCorrupt
+			}
+			dst = emitLiteral(dst, input)
+			continue
+
+		case 1:
+			length = value & 15
+			if offset, ok = readOne(); !ok {
+				return nil, ErrCorrupt
+			}
+			offset = offset<<2 | (value >> 4)
+			if length == 15 {
+				if length, ok = readOne(); !ok {
+					return nil, ErrCorrupt
+				}
+				length += 18
+			} else {
+				length += 4
+			}
+			offset++
+
+		case 2:
+			if offset, ok = readTwo(); !ok {
+				return nil, ErrCorrupt
+			}
+			switch {
+			case value <= 60:
+				length = value + 4
+			case value == 61:
+				if length, ok = readOne(); !ok {
+					return nil, ErrCorrupt
+				}
+				length += 64
+			case value == 62:
+				if length, ok = readTwo(); !ok {
+					return nil, ErrCorrupt
+				}
+				length += 64
+			default:
+				if length, ok = readThree(); !ok {
+					return nil, ErrCorrupt
+				}
+				length += 64
+			}
+			offset += 64
+
+		case 3:
+			isCopy3 := value&1 == 1
+			litLen := value >> 1 & 3
+			if !isCopy3 {
+				if offset, ok = readTwo(); !ok {
+					return nil, ErrCorrupt
+				}
+				length = (value >> 3) + 4
+				litLen++
+				offset += 64
+			} else {
+				v2, ok := readThree()
+				if !ok {
+					return nil, ErrCorrupt
+				}
+				value = value | v2<<6
+				offset = (value >> 9) + 65536
+				value = (value >> 3) & 63
+				switch {
+				case value < 61:
+					length = value + 4
+				case value == 61:
+					if length, ok = readOne(); !ok {
+						return nil, ErrCorrupt
+					}
+					length += 64
+				case value == 62:
+					if length, ok = readTwo(); !ok {
+						return nil, ErrCorrupt
+					}
+					length += 64
+				default:
+					if length, ok = readThree(); !ok {
+						return nil, ErrCorrupt
+					}
+					length += 64
+				}
+			}
+			if litLen > 0 {
+				input, ok := readN(litLen)
+				if !ok {
+					return nil, ErrCorrupt
+				}
+				dst = emitLiteral(dst, input)
+			}
+		}
+
+		emit(int(length))
+	}
+	return dst, nil
+}
@@ -0,0 +1,164 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+import (
+	"crypto/sha256"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/minio/minlz/internal/reference"
+)
+
+// conformance gates TestGoEncoderMatchesReference and the cross-decode round
+// trip below: since minlz allows several valid encodings of the same input,
+// these checks only make sense as an opt-in CI job that can track expected
+// per-file, per-arch digests over time, not as a default `go test` gate.
+var conformance = flag.Bool("conformance", false, "run the cross-implementation conformance corpus")
+
+// conformanceSkip lists "index-label" pairs (matching testFiles) that are
+// known to legitimately diverge from the reference encoder's bytes on some
+// architectures, e.g. because an asm path picks a different (still valid)
+// match than the generic Go encoder. Empty for now; add "GOARCH:i-label"
+// entries here as asm backends are introduced.
+var conformanceSkip = map[string]bool{}
+
+// TestGoEncoderMatchesReference encodes every file in testFiles with Encode
+// and checks the result is byte-identical to internal/reference.EncodeBlock,
+// mirroring goEncoderShouldMatchCppEncoder in the Snappy test suite. It then
+// round-trips each encoding through the other implementation's decoder, so a
+// future asm change cannot silently diverge from the reference decoder on
+// any GOARCH.
+func TestGoEncoderMatchesReference(t *testing.T) {
+	if !*conformance {
+		t.Skip("conformance corpus disabled; pass -conformance to enable")
+	}
+	for i := range testFiles {
+		tf := testFiles[i]
+		t.Run(fmt.Sprint(i, "-", tf.label), func(t *testing.T) {
+			if err := downloadBenchmarkFiles(t, tf.filename); err != nil {
+				t.Skipf("failed to download testdata: %s", err)
+			}
+			if conformanceSkip[runtime.GOARCH+":"+fmt.Sprint(i, "-", tf.label)] {
+				t.Skipf("allowlisted divergence on %s", runtime.GOARCH)
+			}
+
+			bDir := filepath.FromSlash(*benchdataDir)
+			data := readFile(t, filepath.Join(bDir, tf.filename))
+			if tf.sizeLimit > 0 && len(data) > tf.sizeLimit {
+				data = data[:tf.sizeLimit]
+			}
+
+			ref, err := reference.EncodeBlock(data)
+			if err != nil {
+				t.Fatalf("reference.EncodeBlock: %v", err)
+			}
+			got, err := Encode(nil, data, LevelFastest)
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			t.Logf("reference: %d bytes, sha256 %x", len(ref), sha256.Sum256(ref))
+			t.Logf("go:        %d bytes, sha256 %x", len(got), sha256.Sum256(got))
+			if string(got) != string(ref) {
+				t.Fatalf("Encode output does not match reference.EncodeBlock byte-for-byte")
+			}
+
+			// Cross-decode: each implementation must be able to decode the
+			// other's bytes, so neither decoder is relying on an encoder
+			// quirk that happens to never occur in its own output.
+			fromRef, err := Decode(nil, ref)
+			if err != nil {
+				t.Fatalf("Decode(reference output): %v", err)
+			}
+			if string(fromRef) != string(data) {
+				t.Fatalf("Decode(reference output) did not round-trip")
+			}
+			fromGo, err := reference.DecodeBlock(got)
+			if err != nil {
+				t.Fatalf("reference.DecodeBlock(go output): %v", err)
+			}
+			if string(fromGo) != string(data) {
+				t.Fatalf("reference.DecodeBlock(go output) did not round-trip")
+			}
+		})
+	}
+}
+
+// TestReferenceEncodeBlocksCrossBlockRepeat splits each file in testFiles
+// into fixed-size blocks and compares the total size of independently
+// encoding each block with Encode (which always resets its repeat offset)
+// against reference.EncodeBlocks with carryOffset enabled, which carries the
+// final repeat offset of one block into the next. On the repetitive,
+// highly-correlated corpora in testFiles the carrying encoder should never
+// do worse, and its output must still round-trip through
+// reference.DecodeBlocks.
+func TestReferenceEncodeBlocksCrossBlockRepeat(t *testing.T) {
+	if !*conformance {
+		t.Skip("conformance corpus disabled; pass -conformance to enable")
+	}
+	const blockSize = 64 << 10
+	for i := range testFiles {
+		tf := testFiles[i]
+		t.Run(fmt.Sprint(i, "-", tf.label), func(t *testing.T) {
+			if err := downloadBenchmarkFiles(t, tf.filename); err != nil {
+				t.Skipf("failed to download testdata: %s", err)
+			}
+			bDir := filepath.FromSlash(*benchdataDir)
+			data := readFile(t, filepath.Join(bDir, tf.filename))
+			if tf.sizeLimit > 0 && len(data) > tf.sizeLimit {
+				data = data[:tf.sizeLimit]
+			}
+
+			for _, level := range []Level{LevelFastest, LevelBalanced} {
+				independent := 0
+				for off := 0; off < len(data); off += blockSize {
+					end := off + blockSize
+					if end > len(data) {
+						end = len(data)
+					}
+					got, err := Encode(nil, data[off:end], level)
+					if err != nil {
+						t.Fatalf("Encode: %v", err)
+					}
+					independent += len(got)
+				}
+
+				carried, err := reference.EncodeBlocks(data, blockSize, true)
+				if err != nil {
+					t.Fatalf("reference.EncodeBlocks: %v", err)
+				}
+				t.Logf("level %v: independent blocks %d bytes, carried-offset blocks %d bytes", level, independent, len(carried))
+				if len(carried) > independent {
+					t.Errorf("level %v: carried-offset encoding (%d bytes) did not beat independently encoded blocks (%d bytes)", level, len(carried), independent)
+				}
+			}
+
+			carried, err := reference.EncodeBlocks(data, blockSize, true)
+			if err != nil {
+				t.Fatalf("reference.EncodeBlocks: %v", err)
+			}
+			got, err := reference.DecodeBlocks(carried)
+			if err != nil {
+				t.Fatalf("reference.DecodeBlocks: %v", err)
+			}
+			if string(got) != string(data) {
+				t.Fatalf("reference.DecodeBlocks did not round-trip")
+			}
+		})
+	}
+}
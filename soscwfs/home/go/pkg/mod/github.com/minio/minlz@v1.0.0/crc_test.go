@@ -0,0 +1,48 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestWriterCRCDisabled(t *testing.T) {
+	data := []byte("some data written without a checksum")
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, WriterCRC(false))
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// A default reader refuses unchecked chunks.
+	if _, err := io.ReadAll(NewReader(bytes.NewReader(buf.Bytes()))); err == nil {
+		t.Fatal("want error reading unchecked stream with default reader, got nil")
+	}
+
+	// ReaderVerifyCRC(false) trusts them and round-trips.
+	got, err := io.ReadAll(NewReader(bytes.NewReader(buf.Bytes()), ReaderVerifyCRC(false)))
+	if err != nil {
+		t.Fatalf("ReaderVerifyCRC(false) Read: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatal("roundtrip mismatch")
+	}
+}
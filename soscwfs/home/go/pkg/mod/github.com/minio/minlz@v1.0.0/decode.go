@@ -0,0 +1,216 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+// minLZDecodeGo decodes the tag-encoded payload of a MinLZ block (everything
+// after the marker byte and the decoded-length varint) into dst, which must
+// be sized to hold exactly the expected output.
+//
+// It returns 0 on success and a non-zero value if src is corrupt.
+//
+// This is a pure-Go decoder kept in sync with internal/reference.DecodeBlock;
+// architectures with an optimized assembly decoder should dispatch here only
+// as a fallback.
+func minLZDecodeGo(dst, src []byte) int {
+	return decodeWithBase(dst, 0, src)
+}
+
+// decodeWithBase is minLZDecodeGo, generalized to start writing at dst[base:]
+// while still allowing copy offsets to reach back into dst[:base] (used by
+// DecodeDict to resolve offsets into a prepended dictionary).
+func decodeWithBase(dst []byte, base int, src []byte) int {
+	d := base
+	offset := uint32(1)
+
+	readOne := func() (v uint32, ok bool) {
+		if len(src) >= 1 {
+			v, src = uint32(src[0]), src[1:]
+			return v, true
+		}
+		return 0, false
+	}
+	readTwo := func() (v uint32, ok bool) {
+		if len(src) >= 2 {
+			v, src = uint32(src[0])|uint32(src[1])<<8, src[2:]
+			return v, true
+		}
+		return 0, false
+	}
+	readThree := func() (v uint32, ok bool) {
+		if len(src) >= 3 {
+			v, src = uint32(src[0])|uint32(src[1])<<8|uint32(src[2])<<16, src[3:]
+			return v, true
+		}
+		return 0, false
+	}
+	readN := func(n uint32) (v []byte, ok bool) {
+		if uint32(len(src)) >= n {
+			v, src = src[:n], src[n:]
+			return v, true
+		}
+		return nil, false
+	}
+	fits := func(n uint32) bool {
+		return int(n) >= 0 && d+int(n) <= len(dst)
+	}
+
+	for len(src) > 0 {
+		v, ok := readOne()
+		if !ok {
+			return -1
+		}
+		tag := v & 3
+		value := v >> 2
+		var length uint32
+
+		switch tag {
+		case 0:
+			isRepeat := value&1 != 0
+			value >>= 1
+			switch {
+			case value < 29:
+				length = value + 1
+			case value == 29:
+				if length, ok = readOne(); !ok {
+					return -1
+				}
+				length += 30
+			case value == 30:
+				if length, ok = readTwo(); !ok {
+					return -1
+				}
+				length += 30
+			default:
+				if length, ok = readThree(); !ok {
+					return -1
+				}
+				length += 30
+			}
+			if isRepeat {
+				break
+			}
+			if !fits(length) {
+				return -1
+			}
+			input, ok := readN(length)
+			if !ok {
+				return -1
+			}
+			d += copy(dst[d:], input)
+			continue
+
+		case 1:
+			length = value & 15
+			if offset, ok = readOne(); !ok {
+				return -1
+			}
+			offset = offset<<2 | (value >> 4)
+			if length == 15 {
+				if length, ok = readOne(); !ok {
+					return -1
+				}
+				length += 18
+			} else {
+				length += 4
+			}
+			offset++
+
+		case 2:
+			if offset, ok = readTwo(); !ok {
+				return -1
+			}
+			switch {
+			case value <= 60:
+				length = value + 4
+			case value == 61:
+				if length, ok = readOne(); !ok {
+					return -1
+				}
+				length += 64
+			case value == 62:
+				if length, ok = readTwo(); !ok {
+					return -1
+				}
+				length += 64
+			default:
+				if length, ok = readThree(); !ok {
+					return -1
+				}
+				length += 64
+			}
+			offset += 64
+
+		case 3:
+			isCopy3 := value&1 == 1
+			litLen := value >> 1 & 3
+			if !isCopy3 {
+				if offset, ok = readTwo(); !ok {
+					return -1
+				}
+				length = (value >> 3) + 4
+				litLen++
+				offset += 64
+			} else {
+				v2, ok := readThree()
+				if !ok {
+					return -1
+				}
+				value = value | v2<<6
+				offset = (value >> 9) + 65536
+				value = (value >> 3) & 63
+				switch {
+				case value < 61:
+					length = value + 4
+				case value == 61:
+					if length, ok = readOne(); !ok {
+						return -1
+					}
+					length += 64
+				case value == 62:
+					if length, ok = readTwo(); !ok {
+						return -1
+					}
+					length += 64
+				default:
+					if length, ok = readThree(); !ok {
+						return -1
+					}
+					length += 64
+				}
+			}
+			if litLen > 0 {
+				input, ok := readN(litLen)
+				if !ok || !fits(litLen) {
+					return -1
+				}
+				d += copy(dst[d:], input)
+			}
+		}
+
+		if !fits(length) || offset > uint32(d) {
+			return -1
+		}
+		inPos := d - int(offset)
+		for i := uint32(0); i < length; i++ {
+			dst[d] = dst[inPos]
+			d++
+			inPos++
+		}
+	}
+	if d != len(dst) {
+		return -1
+	}
+	return 0
+}
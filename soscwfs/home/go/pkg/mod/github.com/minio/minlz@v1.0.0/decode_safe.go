@@ -0,0 +1,224 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+// decodeGoSafe is an independently-written, deliberately conservative decoder
+// used only to cross-validate Decode/minLZDecodeGo during fuzzing: every read
+// from src and every write to the growing output is bounds-checked before it
+// happens, rather than relying on the pre-sized dst and length-fits-before-
+// write discipline minLZDecodeGo uses. It is slower and allocates as it
+// grows, so it is not used on the normal Decode path; today it has no
+// separate assembly implementation to guard, but keeping a second decoder
+// around means a future optimized path (or a change to minLZDecodeGo itself)
+// has something independent to be checked against.
+func decodeGoSafe(src []byte) ([]byte, error) {
+	isMLZ, lits, block, dLen, err := isMinLZ(src)
+	if err != nil {
+		return nil, err
+	}
+	if !isMLZ {
+		return nil, ErrCorrupt
+	}
+	if dLen > MaxBlockSize {
+		return nil, ErrTooLarge
+	}
+	if lits {
+		out := make([]byte, len(block))
+		copy(out, block)
+		return out, nil
+	}
+
+	out := make([]byte, 0, dLen)
+	offset := 1
+
+	readByte := func() (v byte, ok bool) {
+		if len(block) < 1 {
+			return 0, false
+		}
+		v, block = block[0], block[1:]
+		return v, true
+	}
+	readLE := func(n int) (v uint32, ok bool) {
+		if len(block) < n {
+			return 0, false
+		}
+		for i := 0; i < n; i++ {
+			v |= uint32(block[i]) << (8 * i)
+		}
+		block = block[n:]
+		return v, true
+	}
+	readBytes := func(n uint32) (v []byte, ok bool) {
+		if uint64(len(block)) < uint64(n) {
+			return nil, false
+		}
+		v, block = block[:n], block[n:]
+		return v, true
+	}
+	appendCopy := func(off, length int) bool {
+		if off <= 0 || off > len(out) {
+			return false
+		}
+		from := len(out) - off
+		for i := 0; i < length; i++ {
+			out = append(out, out[from+i])
+		}
+		return true
+	}
+
+	for len(block) > 0 {
+		v, ok := readByte()
+		if !ok {
+			return nil, ErrCorrupt
+		}
+		tag := v & 3
+		value := uint32(v) >> 2
+		var length uint32
+
+		switch tag {
+		case 0:
+			isRepeat := value&1 != 0
+			value >>= 1
+			switch {
+			case value < 29:
+				length = value + 1
+			case value == 29:
+				if length, ok = readLE(1); !ok {
+					return nil, ErrCorrupt
+				}
+				length += 30
+			case value == 30:
+				if length, ok = readLE(2); !ok {
+					return nil, ErrCorrupt
+				}
+				length += 30
+			default:
+				if length, ok = readLE(3); !ok {
+					return nil, ErrCorrupt
+				}
+				length += 30
+			}
+			if isRepeat {
+				if len(out)+int(length) > MaxBlockSize || !appendCopy(offset, int(length)) {
+					return nil, ErrCorrupt
+				}
+				continue
+			}
+			lit, ok := readBytes(length)
+			if !ok {
+				return nil, ErrCorrupt
+			}
+			out = append(out, lit...)
+			continue
+
+		case 1:
+			length = value & 15
+			lo, ok := readLE(1)
+			if !ok {
+				return nil, ErrCorrupt
+			}
+			offset = int(lo<<2|(value>>4)) + 1
+			if length == 15 {
+				if length, ok = readLE(1); !ok {
+					return nil, ErrCorrupt
+				}
+				length += 18
+			} else {
+				length += 4
+			}
+
+		case 2:
+			lo, ok := readLE(2)
+			if !ok {
+				return nil, ErrCorrupt
+			}
+			switch {
+			case value <= 60:
+				length = value + 4
+			case value == 61:
+				if length, ok = readLE(1); !ok {
+					return nil, ErrCorrupt
+				}
+				length += 64
+			case value == 62:
+				if length, ok = readLE(2); !ok {
+					return nil, ErrCorrupt
+				}
+				length += 64
+			default:
+				if length, ok = readLE(3); !ok {
+					return nil, ErrCorrupt
+				}
+				length += 64
+			}
+			offset = int(lo) + 64
+
+		case 3:
+			isCopy3 := value&1 == 1
+			litLen := value >> 1 & 3
+			if !isCopy3 {
+				lo, ok := readLE(2)
+				if !ok {
+					return nil, ErrCorrupt
+				}
+				length = (value >> 3) + 4
+				litLen++
+				offset = int(lo) + 64
+			} else {
+				v2, ok := readLE(3)
+				if !ok {
+					return nil, ErrCorrupt
+				}
+				value |= v2 << 6
+				offset = int(value>>9) + 65536
+				value = (value >> 3) & 63
+				switch {
+				case value < 61:
+					length = value + 4
+				case value == 61:
+					if length, ok = readLE(1); !ok {
+						return nil, ErrCorrupt
+					}
+					length += 64
+				case value == 62:
+					if length, ok = readLE(2); !ok {
+						return nil, ErrCorrupt
+					}
+					length += 64
+				default:
+					if length, ok = readLE(3); !ok {
+						return nil, ErrCorrupt
+					}
+					length += 64
+				}
+			}
+			if litLen > 0 {
+				lit, ok := readBytes(litLen)
+				if !ok {
+					return nil, ErrCorrupt
+				}
+				out = append(out, lit...)
+			}
+		}
+
+		if len(out)+int(length) > MaxBlockSize || !appendCopy(offset, int(length)) {
+			return nil, ErrCorrupt
+		}
+	}
+	if len(out) != dLen {
+		return nil, ErrCorrupt
+	}
+	return out, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestDecodeGoSafeMatchesDecode encodes a handful of inputs at every level
+// and checks that decodeGoSafe agrees with Decode byte-for-byte, so the two
+// independent implementations can catch bugs in either one.
+func TestDecodeGoSafeMatchesDecode(t *testing.T) {
+	inputs := [][]byte{
+		nil,
+		[]byte("a"),
+		[]byte("abcabcabcabcabcabcabc"),
+		bytes.Repeat([]byte("minlz"), 1000),
+		bytes.Repeat([]byte{0}, 1<<16),
+	}
+	for _, in := range inputs {
+		for l := LevelFastest; l <= LevelSmallest; l++ {
+			comp, err := Encode(nil, in, l)
+			if err != nil {
+				t.Fatalf("level %d: Encode: %v", l, err)
+			}
+			want, err := Decode(nil, comp)
+			if err != nil {
+				t.Fatalf("level %d: Decode: %v", l, err)
+			}
+			got, err := decodeGoSafe(comp)
+			if err != nil {
+				t.Fatalf("level %d: decodeGoSafe: %v", l, err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Fatalf("level %d: decodeGoSafe disagrees with Decode on %d-byte input", l, len(in))
+			}
+		}
+	}
+}
+
+// FuzzRoundtrip encodes arbitrary input at every level and checks that both
+// Decode and decodeGoSafe recover the original bytes.
+func FuzzRoundtrip(f *testing.F) {
+	f.Add([]byte(nil))
+	f.Add([]byte("hello world"))
+	f.Add(bytes.Repeat([]byte("ab"), 500))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if len(data) > MaxBlockSize {
+			return
+		}
+		for l := LevelFastest; l <= LevelSmallest; l++ {
+			comp, err := Encode(nil, data, l)
+			if err != nil {
+				t.Fatalf("level %d: Encode: %v", l, err)
+			}
+			got, err := Decode(nil, comp)
+			if err != nil {
+				t.Fatalf("level %d: Decode: %v", l, err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Fatalf("level %d: Decode roundtrip mismatch", l)
+			}
+			gotSafe, err := decodeGoSafe(comp)
+			if err != nil {
+				t.Fatalf("level %d: decodeGoSafe: %v", l, err)
+			}
+			if !bytes.Equal(gotSafe, data) {
+				t.Fatalf("level %d: decodeGoSafe roundtrip mismatch", l)
+			}
+		}
+	})
+}
+
+// FuzzDecodeSafe hammers decodeGoSafe directly with arbitrary bytes: it must
+// never panic, and whenever it succeeds, it must agree with Decode.
+func FuzzDecodeSafe(f *testing.F) {
+	f.Add([]byte{0})
+	f.Add([]byte("\x03\x08\xff\xff\xff"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		got, err := decodeGoSafe(data)
+		want, wantErr := Decode(nil, data)
+		if (err == nil) != (wantErr == nil) {
+			t.Fatalf("error mismatch: decodeGoSafe=%v Decode=%v", err, wantErr)
+		}
+		if err == nil && !bytes.Equal(got, want) {
+			t.Fatalf("decode mismatch: decodeGoSafe=%x Decode=%x", got, want)
+		}
+	})
+}
@@ -0,0 +1,47 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReaderDecodeConcurrent(t *testing.T) {
+	data := []byte(strings.Repeat("decode concurrent roundtrip payload ", 5000))
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, WriterBlockSize(8192))
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	var out bytes.Buffer
+	r := NewReader(bytes.NewReader(buf.Bytes()))
+	n, err := r.DecodeConcurrent(&out, 4)
+	if err != nil {
+		t.Fatalf("DecodeConcurrent: %v", err)
+	}
+	if n != int64(len(data)) {
+		t.Fatalf("DecodeConcurrent returned %d bytes, want %d", n, len(data))
+	}
+	if !bytes.Equal(out.Bytes(), data) {
+		t.Fatal("roundtrip mismatch")
+	}
+}
@@ -0,0 +1,418 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"index/suffixarray"
+	"sort"
+)
+
+// maxDictSize is the largest dictionary that can be used: it must fit within
+// the copy3 offset range (65536 plus a 21-bit field) so every byte of it is
+// reachable.
+const maxDictSize = 64 << 10
+
+// dictMagic and dictVersion identify the self-contained dictionary blob
+// AppendTo writes and LoadDict reads back, the same way indexHeader/
+// indexTrailer frame a stream's seek index.
+const (
+	dictMagic   = "minlzdic"
+	dictVersion = 1
+)
+
+// Dict holds a shared history used to seed the match window before the
+// first byte of a block or stream, similar to a zstd raw dictionary.
+type Dict struct {
+	bytes []byte
+	id    uint32
+}
+
+// NewDict creates a Dict from sample, which is used verbatim as the shared
+// history. sample is truncated to the trailing maxDictSize bytes if larger.
+func NewDict(sample []byte) *Dict {
+	if len(sample) > maxDictSize {
+		sample = sample[len(sample)-maxDictSize:]
+	}
+	b := make([]byte, len(sample))
+	copy(b, sample)
+	return &Dict{bytes: b, id: crc32.ChecksumIEEE(b)}
+}
+
+// ID returns the 32-bit identifier decoders use to verify that a Dict
+// matches the one used to encode a block or stream.
+func (d *Dict) ID() uint32 {
+	if d == nil {
+		return 0
+	}
+	return d.id
+}
+
+// Bytes returns d's raw dictionary content, the same bytes EncodeDict and
+// DecodeDict expect to be passed directly.
+func (d *Dict) Bytes() []byte {
+	if d == nil {
+		return nil
+	}
+	return d.bytes
+}
+
+// chunkPayload returns the bytes written to the dictionary-id stream chunk:
+// a 4-byte little-endian ID followed by the dictionary's own CRC32C, which
+// together let a reader confirm it has the exact dictionary being used.
+func (d *Dict) chunkPayload() []byte {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint32(buf[0:4], d.id)
+	binary.LittleEndian.PutUint32(buf[4:8], crc32.Checksum(d.bytes, crcTable))
+	return buf
+}
+
+// AppendTo serializes d as a magic, a version byte, and its raw dictionary
+// bytes, and appends the result to dst. LoadDict parses it back, e.g. for
+// cmd/mz's `train` subcommand to save a Dict to a file and `-dict` to load
+// one from it.
+func (d *Dict) AppendTo(dst []byte) []byte {
+	dst = append(dst, dictMagic...)
+	dst = append(dst, dictVersion)
+	return append(dst, d.Bytes()...)
+}
+
+// LoadDict parses a dictionary blob written by Dict.AppendTo.
+func LoadDict(b []byte) (*Dict, error) {
+	if len(b) < len(dictMagic)+1 || string(b[:len(dictMagic)]) != dictMagic {
+		return nil, errors.New("minlz: not a dictionary file")
+	}
+	b = b[len(dictMagic):]
+	if b[0] != dictVersion {
+		return nil, fmt.Errorf("minlz: unsupported dictionary version %d", b[0])
+	}
+	return NewDict(b[1:]), nil
+}
+
+// TrainDict builds a Dict of at most size bytes from samples, by picking the
+// 6-byte substrings that recur most often across the samples (falling back
+// to 4-byte substrings to fill any remaining room) and concatenating them.
+// This is a simple frequency-based trainer, not a full zstd-style cover
+// algorithm, but works well for collections of small, structurally similar
+// messages (RPC payloads, log lines, JSON records).
+func TrainDict(samples [][]byte, size int) *Dict {
+	if size > maxDictSize {
+		size = maxDictSize
+	}
+	if size <= 0 {
+		return NewDict(nil)
+	}
+
+	count := func(n int) map[string]int {
+		freq := make(map[string]int)
+		for _, s := range samples {
+			for i := 0; i+n <= len(s); i++ {
+				freq[string(s[i:i+n])]++
+			}
+		}
+		return freq
+	}
+
+	type substr struct {
+		s string
+		n int
+	}
+	rank := func(freq map[string]int) []substr {
+		out := make([]substr, 0, len(freq))
+		for s, n := range freq {
+			if n > 1 {
+				out = append(out, substr{s, n})
+			}
+		}
+		sort.Slice(out, func(i, j int) bool {
+			if out[i].n != out[j].n {
+				return out[i].n > out[j].n
+			}
+			return out[i].s < out[j].s
+		})
+		return out
+	}
+
+	var dict []byte
+	seen := make(map[string]bool)
+	add := func(cands []substr) {
+		for _, c := range cands {
+			if len(dict) >= size {
+				return
+			}
+			if seen[c.s] {
+				continue
+			}
+			seen[c.s] = true
+			dict = append(dict, c.s...)
+		}
+	}
+	add(rank(count(6)))
+	add(rank(count(4)))
+	if len(dict) > size {
+		dict = dict[:size]
+	}
+	return NewDict(dict)
+}
+
+// BuildDict builds a Dict of at most size bytes from samples using a
+// suffix-array index over the whole corpus (via index/suffixarray) to count
+// substring occurrences by binary search instead of a linear scan per
+// candidate, mirroring how zstd's cover trainer scores candidate segments.
+// Candidate substrings are every 8-byte window of every sample; the ones
+// occurring most often across the corpus are concatenated into the
+// dictionary, most frequent first, until size is reached.
+func BuildDict(samples [][]byte, size int) *Dict {
+	if size > maxDictSize {
+		size = maxDictSize
+	}
+	if size <= 0 {
+		return NewDict(nil)
+	}
+
+	const substrLen = 8
+	var corpus []byte
+	for i, s := range samples {
+		if i > 0 {
+			corpus = append(corpus, 0)
+		}
+		corpus = append(corpus, s...)
+	}
+	if len(corpus) < substrLen {
+		return NewDict(corpus)
+	}
+	index := suffixarray.New(corpus)
+
+	type candidate struct {
+		s string
+		n int
+	}
+	var candidates []candidate
+	seen := make(map[string]bool)
+	for _, s := range samples {
+		for i := 0; i+substrLen <= len(s); i++ {
+			sub := string(s[i : i+substrLen])
+			if seen[sub] {
+				continue
+			}
+			seen[sub] = true
+			if n := len(index.Lookup([]byte(sub), -1)); n > 1 {
+				candidates = append(candidates, candidate{sub, n})
+			}
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].n != candidates[j].n {
+			return candidates[i].n > candidates[j].n
+		}
+		return candidates[i].s < candidates[j].s
+	})
+
+	var dict []byte
+	for _, c := range candidates {
+		if len(dict) >= size {
+			break
+		}
+		dict = append(dict, c.s...)
+	}
+	if len(dict) > size {
+		dict = dict[:size]
+	}
+	return NewDict(dict)
+}
+
+// EncodeDict behaves like Encode, but seeds the encoder's hash table and
+// back-reference window with dict before scanning src, so copies may
+// reference bytes from the dictionary.
+func EncodeDict(dst, src, dict []byte, level Level) ([]byte, error) {
+	return encodeDictTableBits(dst, src, dict, level, defaultTableBits)
+}
+
+// encodeDictTableBits is EncodeDict with an explicit match-finder hash-table
+// size; see encodeTableBits.
+func encodeDictTableBits(dst, src, dict []byte, level Level, tableBits int) ([]byte, error) {
+	if len(dict) > maxDictSize {
+		dict = dict[len(dict)-maxDictSize:]
+	}
+	if len(dict) == 0 {
+		return encodeTableBits(dst, src, level, tableBits)
+	}
+	if level < LevelFastest || level > LevelSmallest {
+		return nil, ErrInvalidLevel
+	}
+	if len(src) > MaxBlockSize {
+		return nil, ErrTooLarge
+	}
+
+	markerEnd := len(dst) + 1
+	dst = append(dst, 0)
+	if len(src) == 0 {
+		return dst, nil
+	}
+	hdr := binary.AppendUvarint(dst, uint64(len(src)))
+
+	compressed := encodeBlockWithDictGo(hdr, src, dict, level, tableBits)
+	if compressed != nil {
+		return compressed, nil
+	}
+	return encodeUncompressed(dst[:markerEnd], src), nil
+}
+
+// DecodeDict behaves like Decode, but prepends dict to the window so copies
+// whose offset reaches past the start of src can resolve into it.
+func DecodeDict(dst, src, dict []byte) ([]byte, error) {
+	isMLZ, lits, block, dLen, err := isMinLZ(src)
+	if err != nil {
+		return nil, err
+	}
+	if !isMLZ {
+		return nil, ErrCorrupt
+	}
+	if dLen > MaxBlockSize {
+		return nil, ErrTooLarge
+	}
+	if lits {
+		return append(dst[:0], block...), nil
+	}
+	if len(dict) > maxDictSize {
+		dict = dict[len(dict)-maxDictSize:]
+	}
+
+	// Decode into a scratch buffer that has the dictionary as a prefix, then
+	// return only the part produced for src.
+	window := make([]byte, len(dict)+dLen)
+	copy(window, dict)
+	if decodeWithBase(window, len(dict), block) != 0 {
+		return nil, ErrCorrupt
+	}
+	out := window[len(dict):]
+	if cap(dst) >= len(out) {
+		dst = append(dst[:0], out...)
+		return dst, nil
+	}
+	return out, nil
+}
+
+// encodeBlockWithDictGo is encodeBlockGo, but with the hash table and
+// previous-bytes window prewarmed from dict. Offsets into the dictionary are
+// encoded the same way as any other back-reference: the virtual position of
+// src[0] is len(dict), so a match found at virtual position c < len(dict)
+// simply yields a larger offset.
+func encodeBlockWithDictGo(dst, src, dict []byte, level Level, tableBits int) []byte {
+	const inputMargin = 4
+	if len(src) < 5 {
+		return nil
+	}
+
+	// window = dict ++ src, addressed with virtual positions so offsets
+	// computed below are valid MinLZ copy offsets (distance back from the
+	// current position, irrespective of which half they land in).
+	window := make([]byte, len(dict)+len(src))
+	copy(window, dict)
+	copy(window[len(dict):], src)
+
+	table := make([]uint32, 1<<tableBits)
+	base0 := len(dict)
+	for i := 0; i+4 <= len(dict); i++ {
+		table[hash4(binary.LittleEndian.Uint32(window[i:]), uint8(tableBits))] = uint32(i)
+	}
+
+	sLimit := len(window) - inputMargin
+	dstLimit := len(src) + len(dst) - 11
+	nextEmit := base0
+	s := base0
+	repeat := 1
+
+	for {
+		candidate := 0
+		minSrcPos := s - (2 << 20) - 65535
+		for {
+			if s > sLimit {
+				goto emitRemainder
+			}
+			cv := binary.LittleEndian.Uint32(window[s:])
+			hash := hash4(cv, uint8(tableBits))
+			candidate = int(table[hash])
+			table[hash] = uint32(s)
+			if candidate >= minSrcPos && candidate != s && cv == binary.LittleEndian.Uint32(window[candidate:]) {
+				break
+			}
+			s++
+			minSrcPos++
+		}
+
+		base := s
+		offset := s - candidate
+		candidate += 4
+		s += 4
+		for s < len(window) && window[s] == window[candidate] {
+			candidate++
+			s++
+		}
+		length := s - base
+
+		if nextEmit != base {
+			literals := window[nextEmit:base]
+			canFuse := (len(literals) <= 3 || (offset <= 65535+64 && len(literals) <= 4)) && offset >= 64
+			if canFuse {
+				if offset <= 65535+64 {
+					dst = emitCopyLits2(dst, literals, offset, length)
+				} else {
+					dst = emitCopyLits3(dst, literals, offset, length)
+				}
+				length = 0
+			} else {
+				if len(dst)+len(literals) > dstLimit {
+					return nil
+				}
+				dst = emitLiteral(dst, literals)
+			}
+		}
+		if length > 0 {
+			if offset == repeat {
+				dst = emitRepeat(dst, length)
+			} else {
+				dst = emitCopy(dst, offset, length)
+			}
+		}
+		repeat = offset
+		nextEmit = s
+
+		if s > sLimit {
+			goto emitRemainder
+		}
+		if len(dst) > dstLimit {
+			return nil
+		}
+
+		base++
+		for base < s {
+			table[hash4(binary.LittleEndian.Uint32(window[base:]), uint8(tableBits))] = uint32(base)
+			base++
+		}
+	}
+
+emitRemainder:
+	if nextEmit < len(window) {
+		if len(dst)+len(window)-nextEmit > dstLimit {
+			return nil
+		}
+		dst = emitLiteral(dst, window[nextEmit:])
+	}
+	return dst
+}
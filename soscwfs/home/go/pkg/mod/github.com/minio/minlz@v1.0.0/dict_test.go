@@ -0,0 +1,56 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDictAppendToAndLoadDict(t *testing.T) {
+	samples := [][]byte{
+		[]byte(`{"event":"login","user":"alice"}`),
+		[]byte(`{"event":"login","user":"bob"}`),
+		[]byte(`{"event":"logout","user":"alice"}`),
+	}
+	d := BuildDict(samples, 64)
+	if len(d.Bytes()) == 0 {
+		t.Fatal("BuildDict produced an empty dictionary")
+	}
+
+	blob := d.AppendTo(nil)
+	got, err := LoadDict(blob)
+	if err != nil {
+		t.Fatalf("LoadDict: %v", err)
+	}
+	if !bytes.Equal(got.Bytes(), d.Bytes()) {
+		t.Fatalf("LoadDict roundtrip mismatch: got %q, want %q", got.Bytes(), d.Bytes())
+	}
+	if got.ID() != d.ID() {
+		t.Fatalf("LoadDict ID mismatch: got %d, want %d", got.ID(), d.ID())
+	}
+}
+
+func TestLoadDictRejectsGarbage(t *testing.T) {
+	if _, err := LoadDict([]byte("not a dictionary")); err == nil {
+		t.Fatal("LoadDict accepted a non-dictionary blob")
+	}
+	d := TrainDict([][]byte{[]byte("abcabcabcabc")}, 32)
+	blob := d.AppendTo(nil)
+	blob[len(dictMagic)] = dictVersion + 1
+	if _, err := LoadDict(blob); err == nil {
+		t.Fatal("LoadDict accepted an unsupported version")
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+import "fmt"
+
+// DigestAlg identifies an optional strong per-block digest, computed over
+// the uncompressed block and verified in addition to (not instead of) the
+// regular CRC32C checksum. Unlike the CRC, which only guards against
+// transmission/storage corruption, a strong digest is collision-resistant
+// enough to use for content-addressable deduplication of blocks.
+type DigestAlg byte
+
+const (
+	// DigestNone means no per-block digest is recorded; only the regular
+	// CRC32C checksum (if enabled) protects each block.
+	DigestNone DigestAlg = iota
+	// DigestXXH3_128 records a 128-bit xxh3 digest of each uncompressed
+	// block.
+	DigestXXH3_128
+	// DigestBLAKE3_256 records a 256-bit BLAKE3 digest of each uncompressed
+	// block.
+	DigestBLAKE3_256
+)
+
+// Size returns the digest length in bytes for alg, or 0 for DigestNone.
+func (a DigestAlg) Size() int {
+	switch a {
+	case DigestXXH3_128:
+		return 16
+	case DigestBLAKE3_256:
+		return 32
+	default:
+		return 0
+	}
+}
+
+func (a DigestAlg) String() string {
+	switch a {
+	case DigestNone:
+		return "none"
+	case DigestXXH3_128:
+		return "xxh3-128"
+	case DigestBLAKE3_256:
+		return "blake3-256"
+	default:
+		return fmt.Sprintf("DigestAlg(%d)", byte(a))
+	}
+}
+
+// computeDigest computes the digest of b under alg. DigestXXH3_128 and
+// DigestBLAKE3_256 are only available when built with the matching
+// minlz_xxh3 / minlz_blake3 build tag (see digest_xxh3.go / digest_blake3.go
+// and their no-op counterparts); without it, selecting that algorithm is an
+// error rather than a silently weaker digest.
+func computeDigest(alg DigestAlg, b []byte) ([]byte, error) {
+	switch alg {
+	case DigestNone:
+		return nil, nil
+	case DigestXXH3_128:
+		return xxh3Digest(b)
+	case DigestBLAKE3_256:
+		return blake3Digest(b)
+	default:
+		return nil, fmt.Errorf("minlz: unknown digest algorithm %d", byte(alg))
+	}
+}
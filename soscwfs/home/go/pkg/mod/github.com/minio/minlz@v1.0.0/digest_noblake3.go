@@ -0,0 +1,27 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !minlz_blake3
+
+package minlz
+
+import "errors"
+
+// blake3Digest is a stub used when the minlz_blake3 build tag isn't set. It
+// fails rather than silently falling back to a weaker hash, since the whole
+// point of DigestBLAKE3_256 is a specific, collision-resistant digest that
+// callers may be relying on for content-addressable identity.
+func blake3Digest(b []byte) ([]byte, error) {
+	return nil, errors.New("minlz: DigestBLAKE3_256 support not built in; rebuild with -tags minlz_blake3")
+}
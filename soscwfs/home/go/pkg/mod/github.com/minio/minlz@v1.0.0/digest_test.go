@@ -0,0 +1,71 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDigestAlgSize(t *testing.T) {
+	cases := []struct {
+		alg  DigestAlg
+		size int
+	}{
+		{DigestNone, 0},
+		{DigestXXH3_128, 16},
+		{DigestBLAKE3_256, 32},
+	}
+	for _, c := range cases {
+		if got := c.alg.Size(); got != c.size {
+			t.Errorf("%v.Size() = %d, want %d", c.alg, got, c.size)
+		}
+	}
+}
+
+// TestWriterDigestRequiresBuildTag exercises the hard-error path: without the
+// matching minlz_xxh3/minlz_blake3 build tag, selecting a strong digest
+// algorithm must fail rather than silently fall back to a weaker hash.
+func TestWriterDigestRequiresBuildTag(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, WriterDigest(DigestXXH3_128))
+	_, err := w.Write([]byte(strings.Repeat("data", 100)))
+	if err == nil {
+		err = w.Close()
+	}
+	if err == nil {
+		t.Fatal("want error selecting DigestXXH3_128 without the minlz_xxh3 build tag, got nil")
+	}
+}
+
+func TestStreamInfoReportsDigestAlg(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	if _, err := w.Write([]byte("no digest configured on this writer")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	r := NewReader(bytes.NewReader(buf.Bytes()))
+	if _, err := r.Read(make([]byte, 1)); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if info := r.StreamInfo(); info.DigestAlg != DigestNone {
+		t.Fatalf("StreamInfo().DigestAlg = %v, want DigestNone", info.DigestAlg)
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build minlz_xxh3
+
+package minlz
+
+import "github.com/zeebo/xxh3"
+
+// xxh3Digest computes the 128-bit xxh3 digest of b for DigestXXH3_128.
+func xxh3Digest(b []byte) ([]byte, error) {
+	sum := xxh3.Hash128(b).Bytes()
+	return sum[:], nil
+}
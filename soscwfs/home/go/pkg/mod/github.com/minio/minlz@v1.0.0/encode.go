@@ -0,0 +1,349 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+import "encoding/binary"
+
+// Encode compresses src and appends it to dst, which may be nil.
+// level must be LevelFastest, LevelBalanced or LevelSmallest; all three
+// use the same greedy matcher and produce the same output.
+func Encode(dst, src []byte, level Level) ([]byte, error) {
+	return encodeTableBits(dst, src, level, defaultTableBits)
+}
+
+// encodeTableBits is Encode with an explicit match-finder hash-table size,
+// used directly by the stream Writer so WriterLowMem can shrink the table
+// without going through a separate exported entry point.
+func encodeTableBits(dst, src []byte, level Level, tableBits int) ([]byte, error) {
+	if level < LevelFastest || level > LevelSmallest {
+		return nil, ErrInvalidLevel
+	}
+	if len(src) > MaxBlockSize {
+		return nil, ErrTooLarge
+	}
+
+	markerEnd := len(dst) + 1
+	dst = append(dst, 0)
+	if len(src) == 0 {
+		return dst, nil
+	}
+
+	// Very small blocks never compress well enough to be worth it.
+	if len(src) <= 16 {
+		return encodeUncompressed(dst, src), nil
+	}
+
+	hdr := binary.AppendUvarint(dst, uint64(len(src)))
+	compressed := encodeBlockGo(hdr, src, level, tableBits)
+	if compressed != nil {
+		return compressed, nil
+	}
+	// Not compressible: discard the tag stream written above and fall back
+	// to the "wantSize == 0" uncompressed sentinel.
+	return encodeUncompressed(dst[:markerEnd], src), nil
+}
+
+// encodeUncompressed stores src uncompressed using the zero-length varint
+// sentinel recognized by isMinLZ. dst must already contain the marker byte.
+func encodeUncompressed(dst, src []byte) []byte {
+	dst = append(dst, 0)
+	return append(dst, src...)
+}
+
+// defaultTableBits is the match-finder hash-table size used by encodeBlockGo
+// outside of WriterLowMem mode.
+const defaultTableBits = 16
+
+// encodeBlockGo appends a tag-encoded representation of src to dst, which
+// must already contain the marker byte and the decoded-length varint.
+// It returns nil if the result would not be smaller than just storing src
+// uncompressed. tableBits sizes the match-finder hash table; smaller values
+// trade ratio for less memory (see WriterLowMem).
+func encodeBlockGo(dst, src []byte, level Level, tableBits int) []byte {
+	const inputMargin = 4
+	if len(src) < 5 {
+		return nil
+	}
+
+	table := make([]uint32, 1<<tableBits)
+	sLimit := len(src) - inputMargin
+	dstLimit := len(src) + len(dst) - 11
+	nextEmit := 0
+	s := 1
+	repeat := 1
+
+	for {
+		candidate := 0
+		minSrcPos := s - (2 << 20) - 65535
+		for {
+			if s > sLimit {
+				goto emitRemainder
+			}
+			cv := binary.LittleEndian.Uint32(src[s:])
+			hash := hash4(cv, uint8(tableBits))
+			candidate = int(table[hash])
+			table[hash] = uint32(s)
+			if candidate >= minSrcPos && cv == binary.LittleEndian.Uint32(src[candidate:]) {
+				break
+			}
+			s++
+			minSrcPos++
+		}
+
+		base := s
+		offset := s - candidate
+		candidate += 4
+		s += 4
+		for s < len(src) && src[s] == src[candidate] {
+			candidate++
+			s++
+		}
+		length := s - base
+
+		if nextEmit != base {
+			literals := src[nextEmit:base]
+			canFuse := (len(literals) <= 3 || (offset <= 65535+64 && len(literals) <= 4)) && offset >= 64
+			if canFuse {
+				if offset <= 65535+64 {
+					dst = emitCopyLits2(dst, literals, offset, length)
+				} else {
+					dst = emitCopyLits3(dst, literals, offset, length)
+				}
+				length = 0
+			} else {
+				if len(dst)+len(literals) > dstLimit {
+					return nil
+				}
+				dst = emitLiteral(dst, literals)
+			}
+		}
+		if length > 0 {
+			if offset == repeat {
+				dst = emitRepeat(dst, length)
+			} else {
+				dst = emitCopy(dst, offset, length)
+			}
+		}
+		repeat = offset
+		nextEmit = s
+
+		if s > sLimit {
+			goto emitRemainder
+		}
+		if len(dst) > dstLimit {
+			return nil
+		}
+
+		base++
+		for base < s {
+			table[hash4(binary.LittleEndian.Uint32(src[base:]), uint8(tableBits))] = uint32(base)
+			base++
+		}
+	}
+
+emitRemainder:
+	if nextEmit < len(src) {
+		if len(dst)+len(src)-nextEmit > dstLimit {
+			return nil
+		}
+		dst = emitLiteral(dst, src[nextEmit:])
+	}
+	return dst
+}
+
+func hash4(u uint32, h uint8) uint32 {
+	const prime4bytes = 2654435761
+	return (u * prime4bytes) >> ((32 - h) & 31)
+}
+
+// emitLiteral appends a run of literal bytes to dst.
+func emitLiteral(dst, lits []byte) []byte {
+	if len(lits) == 0 {
+		return dst
+	}
+	const tagLiteral = 0
+	if len(lits) < 30 {
+		dst = append(dst, tagLiteral|uint8(len(lits)-1)<<3)
+		return append(dst, lits...)
+	}
+	n := uint32(len(lits)) - 30
+	if n < 256 {
+		dst = append(dst, tagLiteral|uint8(29)<<3, uint8(n))
+		return append(dst, lits...)
+	}
+	if n < 65536 {
+		var tmp [2]byte
+		binary.LittleEndian.PutUint16(tmp[:], uint16(n))
+		dst = append(dst, tagLiteral|uint8(30)<<3)
+		dst = append(dst, tmp[:]...)
+		return append(dst, lits...)
+	}
+	var tmp [4]byte
+	binary.LittleEndian.PutUint32(tmp[:], n)
+	dst = append(dst, tagLiteral|uint8(31)<<3)
+	dst = append(dst, tmp[:3]...)
+	return append(dst, lits...)
+}
+
+// emitRepeat appends a copy using the last used offset. length must be >= 1.
+func emitRepeat(dst []byte, length int) []byte {
+	const tagRepeat = 0 | 4
+	length--
+	if length < 29 {
+		return append(dst, uint8(length)<<3|tagRepeat)
+	}
+	length -= 29
+	if length < 256 {
+		return append(dst, uint8(29)<<3|tagRepeat, uint8(length))
+	}
+	if length < 65536 {
+		var tmp [2]byte
+		binary.LittleEndian.PutUint16(tmp[:], uint16(length))
+		dst = append(dst, uint8(30)<<3|tagRepeat)
+		return append(dst, tmp[:]...)
+	}
+	var tmp [4]byte
+	binary.LittleEndian.PutUint32(tmp[:], uint32(length))
+	dst = append(dst, uint8(31)<<3|tagRepeat)
+	return append(dst, tmp[:3]...)
+}
+
+// emitCopy appends a copy of the given offset/length, picking the narrowest
+// tag that can represent it. length must be >= 4 and offset >= 1.
+func emitCopy(dst []byte, offset, length int) []byte {
+	switch {
+	case offset <= 1024:
+		return emitCopy1(dst, offset, length)
+	case offset <= 65535+64:
+		return emitCopy2(dst, offset, length)
+	default:
+		return emitCopyLits3(dst, nil, offset, length)
+	}
+}
+
+func emitCopy1(dst []byte, offset, length int) []byte {
+	const tagCopy1 = 1
+	offset--
+	if length <= 18 {
+		var tmp [2]byte
+		binary.LittleEndian.PutUint16(tmp[:], uint16(offset<<6)|uint16(length-4)<<2|tagCopy1)
+		return append(dst, tmp[:]...)
+	}
+	if length <= 273 {
+		var tmp [3]byte
+		binary.LittleEndian.PutUint16(tmp[:2], uint16(offset<<6)|uint16(15)<<2|tagCopy1)
+		tmp[2] = uint8(length - 18)
+		return append(dst, tmp[:]...)
+	}
+	var tmp [2]byte
+	binary.LittleEndian.PutUint16(tmp[:], uint16(offset<<6)|uint16(14)<<2|tagCopy1)
+	dst = append(dst, tmp[:]...)
+	return emitRepeat(dst, length-18)
+}
+
+func emitCopy2(dst []byte, offset, length int) []byte {
+	const tagCopy2 = 2
+	length -= 4
+	offset -= 64
+	var offsetEnc [2]byte
+	offsetEnc[0] = uint8(offset)
+	offsetEnc[1] = uint8(offset >> 8)
+
+	if length <= 60 {
+		dst = append(dst, uint8(length)<<2|tagCopy2)
+		return append(dst, offsetEnc[:]...)
+	}
+	length -= 60
+	if length < 256 {
+		dst = append(dst, tagCopy2|uint8(61)<<2)
+		dst = append(dst, offsetEnc[:]...)
+		return append(dst, uint8(length))
+	}
+	if length < 65536 {
+		var tmp [2]byte
+		binary.LittleEndian.PutUint16(tmp[:], uint16(length))
+		dst = append(dst, tagCopy2|uint8(62)<<2)
+		dst = append(dst, offsetEnc[:]...)
+		return append(dst, tmp[:]...)
+	}
+	var tmp [4]byte
+	binary.LittleEndian.PutUint32(tmp[:], uint32(length))
+	dst = append(dst, tagCopy2|uint8(63)<<2)
+	dst = append(dst, offsetEnc[:]...)
+	return append(dst, tmp[:3]...)
+}
+
+// emitCopyLits2 appends 1-4 literals fused with a 2-byte-offset copy.
+func emitCopyLits2(dst, lits []byte, offset, length int) []byte {
+	const tagCopyLits2 = 3
+	length -= 4
+	offset -= 64
+	if length > 7 {
+		dst = append(dst, tagCopyLits2|uint8(7<<5)|uint8(len(lits)-1)<<3)
+		var tmp [2]byte
+		binary.LittleEndian.PutUint16(tmp[:], uint16(offset))
+		dst = append(dst, tmp[:]...)
+		dst = append(dst, lits...)
+		return emitRepeat(dst, length-7)
+	}
+	dst = append(dst, tagCopyLits2|uint8(length<<5)|uint8(len(lits)-1)<<3)
+	var tmp [2]byte
+	binary.LittleEndian.PutUint16(tmp[:], uint16(offset))
+	dst = append(dst, tmp[:]...)
+	return append(dst, lits...)
+}
+
+// emitCopyLits3 appends an optionally-fused copy with a 21-bit offset
+// field (offsets 65536 up to 65536+2^21-1).
+// lits may be nil for an unfused copy.
+func emitCopyLits3(dst, lits []byte, offset, length int) []byte {
+	length -= 4
+	const tagCopy3 = 3 | 4
+	offset -= 65536
+
+	encoded := uint32(tagCopy3)
+	encoded |= uint32(len(lits)) << 3
+	encoded |= uint32(offset) << 11
+
+	var buf [4]byte
+	switch {
+	case length <= 60:
+		encoded |= uint32(length) << 5
+		binary.LittleEndian.PutUint32(buf[:], encoded)
+		return append(append(dst, buf[:]...), lits...)
+	case length-60 < 256:
+		length -= 60
+		encoded |= uint32(61) << 5
+		binary.LittleEndian.PutUint32(buf[:], encoded)
+		dst = append(dst, buf[:]...)
+		return append(append(dst, uint8(length)), lits...)
+	case length-60 < 65536:
+		length -= 60
+		encoded |= uint32(62) << 5
+		binary.LittleEndian.PutUint32(buf[:], encoded)
+		dst = append(dst, buf[:]...)
+		var tmp [2]byte
+		binary.LittleEndian.PutUint16(tmp[:], uint16(length))
+		return append(append(dst, tmp[:]...), lits...)
+	default:
+		length -= 60
+		encoded |= uint32(63) << 5
+		binary.LittleEndian.PutUint32(buf[:], encoded)
+		dst = append(dst, buf[:]...)
+		var tmp [4]byte
+		binary.LittleEndian.PutUint32(tmp[:], uint32(length))
+		return append(append(dst, tmp[:3]...), lits...)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+)
+
+// ReaderFallback controls whether Read accepts input that doesn't carry a
+// framed MinLZ stream's own magic header. When enabled, the first Read
+// sniffs the source (see DetectFormat) and, if it looks like a Snappy/S2
+// stream, gzip, zstd, or a raw MinLZ block rather than a framed MinLZ
+// stream, transparently decodes it that way instead of failing with
+// ErrCorrupt -- the same detection NewAutoReader applies standalone, wired
+// into Reader so a caller that doesn't control what produced its input (a
+// CLI's stdin, an upload) can still just call Read.
+func ReaderFallback(enabled bool) ReaderOption {
+	return func(r *Reader) error {
+		r.fallback = enabled
+		return nil
+	}
+}
+
+// initFallback sniffs r's underlying source on the first Read and installs
+// fallbackRC if it turns out not to be a framed MinLZ stream.
+func (r *Reader) initFallback() error {
+	r.sniffed = true
+	br, ok := r.r.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReaderSize(r.r, autoDetectPeekLen)
+		r.r = br
+	}
+	peek, _ := br.Peek(autoDetectPeekLen)
+	format, _ := DetectFormat(peek)
+	switch format {
+	case FormatSnappyStream:
+		r.fallbackRC = io.NopCloser(NewSnappyReader(br))
+	case FormatGzip:
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return err
+		}
+		r.fallbackRC = gz
+	case FormatZstd:
+		zr, err := newZstdReader(br)
+		if err != nil {
+			return err
+		}
+		r.fallbackRC = zr
+	case FormatMinLZBlock:
+		raw, err := io.ReadAll(br)
+		if err != nil {
+			return err
+		}
+		decoded, err := Decode(nil, raw)
+		if err != nil {
+			return err
+		}
+		r.fallbackRC = io.NopCloser(bytes.NewReader(decoded))
+	default:
+		// FormatMinLZStream or FormatUnknown: let the normal chunk-reading
+		// path handle it and report whatever error it finds.
+	}
+	return nil
+}
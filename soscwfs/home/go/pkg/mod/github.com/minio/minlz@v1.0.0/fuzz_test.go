@@ -16,6 +16,7 @@ package minlz
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"hash/crc32"
 	"io"
@@ -70,8 +71,11 @@ func FuzzEncodingBlocks(f *testing.F) {
 			}
 		}(decDst[len(decDst)-4:])
 		decDst = decDst[:len(data):len(data)]
-		const levelReference = LevelSmallest + 1
-		for l := LevelFastest; l <= levelReference; l++ {
+		const (
+			levelReference       = LevelSmallest + 1
+			levelReferenceBetter = levelReference + 1
+		)
+		for l := LevelFastest; l <= levelReferenceBetter; l++ {
 			for i := range decDst {
 				decDst[i] = 0xfe
 			}
@@ -79,10 +83,13 @@ func FuzzEncodingBlocks(f *testing.F) {
 				compDst[i] = 0xff
 			}
 			var comp []byte
-			if l < levelReference {
+			switch {
+			case l < levelReference:
 				comp, _ = Encode(nil, data, l)
-			} else {
+			case l == levelReference:
 				comp, _ = reference.EncodeBlock(data)
+			default:
+				comp, _ = reference.EncodeBlockBetter(data)
 			}
 			decoded, err := decodeGo(decDst, comp)
 			if err != nil {
@@ -371,3 +378,93 @@ func FuzzStreamDecode(f *testing.F) {
 		}
 	})
 }
+
+// buildReferenceIndexBody encodes an index body in the exact format
+// reference.LoadIndexAfterHeader/reference.NewIndexScanner parse: the
+// uncompressed-offset deltas for every entry (written only when
+// hasUncompressed is set), then the compressed-offset ones, each relative to reference's running estBlockUncomp/cPredict state,
+// followed by a 4-byte size field (unchecked by either parser) and the
+// trailer magic.
+func buildReferenceIndexBody(total, totalComp, estBlockUncomp int64, hasUncompressed bool, offsets []struct{ U, C int64 }) []byte {
+	body := []byte(reference.IndexHeader)
+	body = binary.AppendVarint(body, total)
+	body = binary.AppendVarint(body, totalComp)
+	body = binary.AppendVarint(body, estBlockUncomp)
+	body = binary.AppendVarint(body, int64(len(offsets)))
+	if hasUncompressed {
+		body = append(body, 1)
+	} else {
+		body = append(body, 0)
+	}
+	var prevU int64
+	for idx, e := range offsets {
+		if !hasUncompressed {
+			continue
+		}
+		delta := e.U
+		if idx > 0 {
+			delta = e.U - prevU - estBlockUncomp
+		}
+		body = binary.AppendVarint(body, delta)
+		prevU = e.U
+	}
+	var prevC, cPredict int64 = 0, estBlockUncomp / 2
+	for idx, e := range offsets {
+		delta := e.C
+		if idx > 0 {
+			delta = e.C - prevC - cPredict
+			cPredict += delta / 2
+		}
+		body = binary.AppendVarint(body, delta)
+		prevC = e.C
+	}
+	body = append(body, 0, 0, 0, 0)
+	body = append(body, reference.IndexTrailer...)
+	return body
+}
+
+// FuzzReferenceIndexScanner checks that reference.IndexScanner (iterated via
+// Next/Block/Err) agrees block-for-block with reference.LoadIndexAfterHeader
+// on the same bytes, for both well-formed indexes built by
+// buildReferenceIndexBody and arbitrary/corrupted byte strings.
+func FuzzReferenceIndexScanner(f *testing.F) {
+	seed := func(total, totalComp, estBlockUncomp int64, hasUncompressed bool, offsets []struct{ U, C int64 }) {
+		f.Add(buildReferenceIndexBody(total, totalComp, estBlockUncomp, hasUncompressed, offsets))
+	}
+	seed(0, -1, 1024, true, nil)
+	seed(4096, -1, 1024, true, []struct{ U, C int64 }{{0, 0}, {1024, 300}, {2048, 610}, {3072, 900}})
+	seed(4096, 1200, 1024, false, []struct{ U, C int64 }{{0, 0}, {1024, 300}, {2048, 610}, {3072, 900}})
+	seed(0, -1, 512, true, []struct{ U, C int64 }{{0, 0}})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		want, wantErr := reference.LoadIndexAfterHeader(data)
+
+		s, err := reference.NewIndexScanner(bytes.NewReader(data))
+		if err != nil {
+			if wantErr == nil {
+				t.Fatalf("NewIndexScanner failed but LoadIndexAfterHeader succeeded: %v", err)
+			}
+			return
+		}
+		var got []struct{ C, U int64 }
+		for s.Next() {
+			c, u := s.Block()
+			got = append(got, struct{ C, U int64 }{c, u})
+		}
+		gotErr := s.Err()
+		if (wantErr == nil) != (gotErr == nil) {
+			t.Fatalf("error mismatch: LoadIndexAfterHeader=%v, IndexScanner=%v", wantErr, gotErr)
+		}
+		if wantErr != nil {
+			return
+		}
+		if len(got) != len(want.Blocks) {
+			t.Fatalf("block count mismatch: scanner=%d, LoadIndexAfterHeader=%d", len(got), len(want.Blocks))
+		}
+		for i, b := range want.Blocks {
+			if got[i].C != b.CompressedOffset || got[i].U != b.UncompressedOffset {
+				t.Fatalf("block %d mismatch: scanner={%d,%d}, LoadIndexAfterHeader={%d,%d}", i, got[i].C, got[i].U, b.CompressedOffset, b.UncompressedOffset)
+			}
+		}
+	})
+}
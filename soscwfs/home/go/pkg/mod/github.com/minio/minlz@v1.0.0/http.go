@@ -0,0 +1,75 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ServeMinLZ serves the uncompressed content of a seekable MinLZ stream (one
+// written with WriterSeekable(true) or SeekableWriter) over HTTP, honoring
+// Range requests in uncompressed space: a client asking for bytes 1000-1999
+// of the decompressed object only causes the block(s) covering that range to
+// be decoded from ra, not the whole blob. This gives object stores the same
+// "pull a sub-range of a compressed object" capability eStargz and
+// zstd-chunked give container image layers.
+//
+// It loads the stream's trailing index (see LoadIndex) to build a
+// SeekableReader, then hands off to http.ServeContent, which takes care of
+// parsing the Range header, setting Accept-Ranges and Content-Range, and
+// handling conditional/HEAD requests.
+func ServeMinLZ(w http.ResponseWriter, r *http.Request, ra io.ReaderAt, size int64) error {
+	sr, err := OpenSeekableReader(ra, size)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return err
+	}
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/octet-stream")
+	}
+	http.ServeContent(w, r, "", time.Time{}, sr)
+	return nil
+}
+
+// MinLZRangeReader returns a reader over the uncompressed byte range
+// [start, end) of the MinLZ object at url, served by an HTTP server that
+// honors Range requests in uncompressed space (see ServeMinLZ): the server
+// already does whatever block decoding is needed to satisfy the range, so
+// the client just issues a single Range request and streams the response
+// body back. An end of -1 means "through the end of the object".
+func MinLZRangeReader(ctx context.Context, url string, start, end int64) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if end < 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", start))
+	} else {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end-1))
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		return nil, fmt.Errorf("minlz: range request to %s returned status %s", url, resp.Status)
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServeMinLZRangeRequest(t *testing.T) {
+	data := []byte(strings.Repeat("serve minlz over http range requests ", 5000))
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, WriterSeekable(true), WriterBlockSize(8192))
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	raw := buf.Bytes()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if err := ServeMinLZ(rw, req, bytes.NewReader(raw), int64(len(raw))); err != nil {
+			t.Errorf("ServeMinLZ: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	const start, end = 100, 5100
+	rc, err := MinLZRangeReader(context.Background(), srv.URL, start, end)
+	if err != nil {
+		t.Fatalf("MinLZRangeReader: %v", err)
+	}
+	defer rc.Close()
+
+	got, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if want := data[start:end]; !bytes.Equal(got, want) {
+		t.Fatalf("got %d bytes, want %d bytes matching data[%d:%d]", len(got), len(want), start, end)
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestEnableIndexAndAddUserChunk(t *testing.T) {
+	data := []byte(strings.Repeat("toc-enabled seekable stream ", 500))
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, WriterBlockSize(2048))
+	w.EnableIndex(true)
+	if err := w.AddUserChunk(MinUserSkippableChunk, []byte("sidecar metadata")); err != nil {
+		t.Fatalf("AddUserChunk: %v", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	sr, err := NewSeekableReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("NewSeekableReader: %v", err)
+	}
+	got := make([]byte, 10)
+	if _, err := sr.ReadAt(got, 3000); err != nil {
+		t.Fatalf("ReadAt: %v", err)
+	}
+	if !bytes.Equal(got, data[3000:3010]) {
+		t.Fatalf("ReadAt(3000) = %q, want %q", got, data[3000:3010])
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fuzz seeds the package's Fuzz* test functions from zip archives
+// of regression and corpus data, rather than checking hundreds of
+// individual seed files into testdata/fuzz.
+package fuzz
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strconv"
+	"testing"
+)
+
+// Type identifies how a corpus zip's entries should be interpreted.
+type Type int
+
+const (
+	// TypeRaw treats every zip entry's bytes as the seed verbatim.
+	TypeRaw Type = iota
+	// TypeGoFuzz treats every zip entry as a corpus file in the format
+	// Go's native fuzzing writes under testdata/fuzz/<FuzzName> ("go test
+	// fuzz v1" followed by a []byte(...) literal), unwrapping it to the
+	// seed bytes it encodes.
+	TypeGoFuzz
+)
+
+// AddFromZip adds every entry of the zip archive at path as a seed via
+// f.Add, interpreting entries according to typ. A missing or unreadable
+// archive (corpus zips are large and not every checkout has fetched them)
+// is logged and skipped rather than failing the test. If short is true,
+// this call is a no-op, for corpora only worth walking in a full test run.
+func AddFromZip(f *testing.F, path string, typ Type, short bool) {
+	if short {
+		return
+	}
+	ReturnFromZip(f, path, typ, func(b []byte) { f.Add(b) })
+}
+
+// ReturnFromZip is AddFromZip, but passes each entry's decoded payload to
+// fn instead of assuming the fuzz target takes it as its only argument --
+// for targets (e.g. FuzzDecode) that seed themselves from data derived from
+// the corpus rather than the corpus bytes themselves.
+func ReturnFromZip(f *testing.F, path string, typ Type, fn func([]byte)) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		f.Logf("fuzz: skipping corpus %s: %v", path, err)
+		return
+	}
+	defer zr.Close()
+	for _, file := range zr.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			f.Logf("fuzz: skipping %s in %s: %v", file.Name, path, err)
+			continue
+		}
+		b, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			f.Logf("fuzz: skipping %s in %s: %v", file.Name, path, err)
+			continue
+		}
+		if typ == TypeGoFuzz {
+			b = decodeGoFuzzCorpus(b)
+		}
+		fn(b)
+	}
+}
+
+// goFuzzHeader is the first line of a corpus file written by Go's native
+// fuzzing support.
+const goFuzzHeader = "go test fuzz v1"
+
+// decodeGoFuzzCorpus extracts the seed bytes from a single-[]byte-argument
+// Go native fuzzing corpus file ("go test fuzz v1\n[]byte(\"...\")\n"). If
+// b doesn't match that shape, it's returned unchanged, so a zip that mixes
+// raw and encoded entries still degrades gracefully.
+func decodeGoFuzzCorpus(b []byte) []byte {
+	header, rest, ok := bytes.Cut(b, []byte("\n"))
+	if !ok || string(header) != goFuzzHeader {
+		return b
+	}
+	line := bytes.TrimSpace(rest)
+	const prefix, suffix = "[]byte(", ")"
+	if !bytes.HasPrefix(line, []byte(prefix)) || !bytes.HasSuffix(line, []byte(suffix)) {
+		return b
+	}
+	quoted := line[len(prefix) : len(line)-len(suffix)]
+	s, err := strconv.Unquote(string(quoted))
+	if err != nil {
+		return b
+	}
+	return []byte(s)
+}
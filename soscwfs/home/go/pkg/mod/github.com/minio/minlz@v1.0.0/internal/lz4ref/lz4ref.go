@@ -0,0 +1,219 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lz4ref is a minimal, independent implementation of the raw LZ4
+// block format (no frame header), used only to generate and decode genuine
+// LZ4 blocks for the minlz package's LZ4 converter tests -- it exists so
+// those tests don't depend on a real LZ4 library being vendored, not as a
+// fast or well-compressing encoder.
+package lz4ref
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	minMatch      = 4
+	hashLog       = 16
+	hashTableSize = 1 << hashLog
+	// mfLimit is how many trailing bytes of src are always left for the
+	// final literal-only sequence, so the match finder never has to reason
+	// about running off the end of the input mid-match.
+	mfLimit = 12
+)
+
+// CompressBlockBound returns the largest size CompressBlock might need to
+// compress n bytes: n, plus one extra length byte per 255 bytes (for
+// literal/match-length overflow), plus a small fixed margin. This mirrors
+// the bound real LZ4 implementations expose.
+func CompressBlockBound(n int) int {
+	if n <= 0 {
+		return 16
+	}
+	return n + n/255 + 16
+}
+
+func hash4(x uint32) uint32 {
+	return (x * 2654435761) >> (32 - hashLog)
+}
+
+// CompressBlock compresses src into dst using the LZ4 block format, with a
+// simple greedy matcher over a single-entry hash table (not tuned for ratio
+// or speed). It returns 0, nil if src is too small to be worth compressing,
+// the same "store uncompressed instead" signal real LZ4 bindings give, and
+// an error if dst is smaller than CompressBlockBound(len(src)).
+func CompressBlock(src, dst []byte) (int, error) {
+	n := len(src)
+	if n < minMatch+mfLimit {
+		return 0, nil
+	}
+	if len(dst) < CompressBlockBound(n) {
+		return 0, errors.New("lz4ref: dst too small")
+	}
+
+	var table [hashTableSize]int32
+	for i := range table {
+		table[i] = -1
+	}
+
+	out := dst[:0]
+	writeLength := func(l int) {
+		for l >= 255 {
+			out = append(out, 255)
+			l -= 255
+		}
+		out = append(out, byte(l))
+	}
+
+	anchor := 0
+	pos := 0
+	limit := n - mfLimit
+	for pos < limit {
+		seq := binary.LittleEndian.Uint32(src[pos:])
+		h := int(hash4(seq))
+		ref := int(table[h])
+		table[h] = int32(pos)
+
+		if ref < 0 || pos-ref > 0xffff || binary.LittleEndian.Uint32(src[ref:]) != seq {
+			pos++
+			continue
+		}
+
+		mlen := minMatch
+		for pos+mlen < n && src[ref+mlen] == src[pos+mlen] {
+			mlen++
+		}
+
+		litLen := pos - anchor
+		mlenField := mlen - minMatch
+		token := byte(0)
+		if litLen < 15 {
+			token = byte(litLen) << 4
+		} else {
+			token = 0xf0
+		}
+		if mlenField < 15 {
+			token |= byte(mlenField)
+		} else {
+			token |= 0xf
+		}
+		out = append(out, token)
+		if litLen >= 15 {
+			writeLength(litLen - 15)
+		}
+		out = append(out, src[anchor:pos]...)
+
+		offset := pos - ref
+		out = append(out, byte(offset), byte(offset>>8))
+		if mlenField >= 15 {
+			writeLength(mlenField - 15)
+		}
+
+		pos += mlen
+		anchor = pos
+	}
+
+	// Final sequence: whatever's left is emitted as literals only, per the
+	// LZ4 block format's requirement that a block never ends in a match.
+	litLen := n - anchor
+	token := byte(0)
+	if litLen < 15 {
+		token = byte(litLen) << 4
+	} else {
+		token = 0xf0
+	}
+	out = append(out, token)
+	if litLen >= 15 {
+		writeLength(litLen - 15)
+	}
+	out = append(out, src[anchor:n]...)
+
+	return len(out), nil
+}
+
+// UncompressBlock decodes a raw LZ4 block (as produced by CompressBlock, or
+// any spec-conforming LZ4 encoder) from src into dst, returning the number
+// of bytes written. It returns a negative value if src is corrupt or dst is
+// too small, matching the convention real LZ4 bindings use.
+func UncompressBlock(dst, src []byte) int {
+	di, si := 0, 0
+	for si < len(src) {
+		token := src[si]
+		si++
+
+		litLen := int(token >> 4)
+		if litLen == 15 {
+			for {
+				if si >= len(src) {
+					return -1
+				}
+				b := src[si]
+				si++
+				litLen += int(b)
+				if b != 0xff {
+					break
+				}
+			}
+		}
+		if litLen > 0 {
+			if si+litLen > len(src) || di+litLen > len(dst) {
+				return -1
+			}
+			copy(dst[di:di+litLen], src[si:si+litLen])
+			di += litLen
+			si += litLen
+		}
+
+		if si == len(src) {
+			// Final sequence: literals only, no match part.
+			return di
+		}
+		if si+2 > len(src) {
+			return -1
+		}
+		offset := int(binary.LittleEndian.Uint16(src[si:]))
+		si += 2
+		if offset == 0 || offset > di {
+			return -1
+		}
+
+		matchLen := int(token & 0xf)
+		if matchLen == 15 {
+			for {
+				if si >= len(src) {
+					return -1
+				}
+				b := src[si]
+				si++
+				matchLen += int(b)
+				if b != 0xff {
+					break
+				}
+			}
+		}
+		matchLen += minMatch
+
+		if di+matchLen > len(dst) {
+			return -1
+		}
+		matchPos := di - offset
+		for i := 0; i < matchLen; i++ {
+			dst[di] = dst[matchPos]
+			di++
+			matchPos++
+		}
+	}
+	return di
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reference
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// errMixedRepeatMode is returned by DecodeBlocks when a concatenated stream
+// contains both carry-offset and reset-offset entries.
+var errMixedRepeatMode = errors.New("reference: stream mixes carry-offset and reset-offset blocks")
+
+// EncodeBlocks splits src into blockSize-sized blocks and encodes each with
+// EncodeBlock (or EncodeBlockFrom, if carryOffset is set), concatenating the
+// results as a sequence of length-prefixed entries DecodeBlocks can read
+// back. Each entry is a uvarint byte length, a one-byte repeat-offset mode
+// (0 = reset, 1 = carry), and the encoded block itself.
+//
+// If carryOffset is true, each block after the first starts with the
+// previous block's final repeat offset as its own initial repeat offset,
+// letting the encoder exploit cross-block repeats in highly correlated
+// input (e.g. data converted from independently-encoded LZ4 frames) that it
+// would otherwise have to re-encode as fresh copies or literals in every
+// block.
+func EncodeBlocks(src []byte, blockSize int, carryOffset bool) ([]byte, error) {
+	if blockSize <= 0 {
+		return nil, fmt.Errorf("reference: invalid block size %d", blockSize)
+	}
+	var dst []byte
+	repeat := 1
+	mode := byte(0)
+	if carryOffset {
+		mode = 1
+	}
+	for len(src) > 0 {
+		n := blockSize
+		if n > len(src) {
+			n = len(src)
+		}
+		chunk := src[:n]
+		src = src[n:]
+
+		var block []byte
+		var err error
+		if carryOffset {
+			block, repeat, err = EncodeBlockFrom(chunk, repeat)
+		} else {
+			block, err = EncodeBlock(chunk)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		dst = binary.AppendUvarint(dst, uint64(len(block)+1))
+		dst = append(dst, mode)
+		dst = append(dst, block...)
+	}
+	return dst, nil
+}
+
+// DecodeBlocks decodes a sequence of length-prefixed blocks produced by
+// EncodeBlocks, concatenating their decoded output. All entries in a stream
+// must use the same repeat-offset mode (see EncodeBlocks); a stream mixing
+// modes is rejected with errMixedRepeatMode.
+func DecodeBlocks(src []byte) ([]byte, error) {
+	var dst []byte
+	mode := int8(-1)
+	offset := uint32(1)
+
+	for len(src) > 0 {
+		entryLen, n := binary.Uvarint(src)
+		if n <= 0 {
+			return nil, fmt.Errorf("reference: invalid block length prefix")
+		}
+		src = src[n:]
+		if entryLen < 1 || uint64(len(src)) < entryLen {
+			return nil, fmt.Errorf("reference: truncated block entry")
+		}
+		entry := src[:entryLen]
+		src = src[entryLen:]
+
+		flag := entry[0]
+		if flag > 1 {
+			return nil, fmt.Errorf("reference: unknown repeat-offset mode %d", flag)
+		}
+		if mode == -1 {
+			mode = int8(flag)
+		} else if mode != int8(flag) {
+			return nil, errMixedRepeatMode
+		}
+
+		initOffset := uint32(1)
+		if flag == 1 {
+			initOffset = offset
+		}
+		block, final, err := decodeBlockFrom(entry[1:], initOffset)
+		if err != nil {
+			return nil, err
+		}
+		dst = append(dst, block...)
+		offset = final
+	}
+	return dst, nil
+}
@@ -24,20 +24,30 @@ const maxBlockSize = 8 << 20
 // DecodeBlock is a reference implementation of the MinLZ block decoder.
 // This implementation is not optimized for speed, but for readability with no dependencies.
 func DecodeBlock(src []byte) (dst []byte, err error) {
+	dst, _, err = decodeBlockFrom(src, 1)
+	return dst, err
+}
+
+// decodeBlockFrom is DecodeBlock's body, parameterized by the repeat
+// offset to start with and returning the offset left active at the end of
+// the block (1 for an empty or uncompressed block, as EncodeBlockFrom
+// reports for it). DecodeBlocks uses this to carry the previous block's
+// final offset forward as the next block's initial repeat offset.
+func decodeBlockFrom(src []byte, initOffset uint32) (dst []byte, finalOffset uint32, err error) {
 	// Print every operation.
 	const debug = false
 
 	if len(src) == 0 {
-		return nil, errors.New("src length is zero")
+		return nil, 0, errors.New("src length is zero")
 	}
 	// Check if first byte is 0.
 	if src[0] != 0 {
-		return nil, errors.New("first byte is not 0")
+		return nil, 0, errors.New("first byte is not 0")
 	}
 
 	// If 0 is the only byte, this is a size 0 slice.
 	if len(src) == 1 && src[0] == 0 {
-		return []byte{}, nil
+		return []byte{}, 1, nil
 	}
 
 	// Skip first byte
@@ -49,15 +59,15 @@ func DecodeBlock(src []byte) (dst []byte, err error) {
 	for i := uint(0); i < 100; i += 7 {
 		if i == 7*10 {
 			// Value exceeds 64 bits.
-			return nil, fmt.Errorf("invalid destination size")
+			return nil, 0, fmt.Errorf("invalid destination size")
 		}
 		if len(src) == 0 {
-			return nil, errors.New("unable to read length")
+			return nil, 0, errors.New("unable to read length")
 		}
 		v := src[0]
 		wantSize |= int(v&0x7f) << i
 		if wantSize > maxBlockSize {
-			return nil, fmt.Errorf("invalid destination size")
+			return nil, 0, fmt.Errorf("invalid destination size")
 		}
 		src = src[1:]
 		if v&0x80 == 0 {
@@ -68,18 +78,18 @@ func DecodeBlock(src []byte) (dst []byte, err error) {
 	// Check if the destination size is valid.
 	// Can be omitted when we control the uvarint reader as above.
 	if wantSize < 0 || wantSize > maxBlockSize {
-		return nil, fmt.Errorf("invalid destination size %d", wantSize)
+		return nil, 0, fmt.Errorf("invalid destination size %d", wantSize)
 	}
 
 	// If the size is 0, return the remaining bytes as literals
 	if wantSize == 0 {
-		return src, nil
+		return src, 1, nil
 	}
 
 	// The decompressed size (after removing the header)
 	// must same or bigger than compressed size.
 	if wantSize < len(src) {
-		return nil, fmt.Errorf("decompressed smaller than compressed size %d", wantSize)
+		return nil, 0, fmt.Errorf("decompressed smaller than compressed size %d", wantSize)
 	}
 
 	// Create output with capacity of wantSize.
@@ -136,7 +146,7 @@ func DecodeBlock(src []byte) (dst []byte, err error) {
 
 	// Offset is retained between operations and initialized to 1.
 	// This is used for repeat offsets.
-	var offset = uint32(1)
+	var offset = initOffset
 
 	// While we have input left.
 	for len(src) > 0 {
@@ -163,7 +173,7 @@ func DecodeBlock(src []byte) (dst []byte, err error) {
 				// 1 byte length
 				length, ok = readOne()
 				if !ok {
-					return nil, fmt.Errorf("lit tag 29: unable to read length at dst pos %d", len(dst))
+					return nil, 0, fmt.Errorf("lit tag 29: unable to read length at dst pos %d", len(dst))
 				}
 				// Add base offset
 				length += 30
@@ -171,14 +181,14 @@ func DecodeBlock(src []byte) (dst []byte, err error) {
 				// 2 byte length
 				length, ok = readTwo()
 				if !ok {
-					return nil, fmt.Errorf("lit tag 30: unable to read length at dst pos %d", len(dst))
+					return nil, 0, fmt.Errorf("lit tag 30: unable to read length at dst pos %d", len(dst))
 				}
 				length += 30
 			case value == 31:
 				// 3 byte length
 				length, ok = readThree()
 				if !ok {
-					return nil, fmt.Errorf("lit tag 31: unable to read length at dst pos %d", len(dst))
+					return nil, 0, fmt.Errorf("lit tag 31: unable to read length at dst pos %d", len(dst))
 				}
 				length += 30
 			}
@@ -195,14 +205,14 @@ func DecodeBlock(src []byte) (dst []byte, err error) {
 
 			// Check if we have enough output space.
 			if !checkDstSize(length) {
-				return nil, fmt.Errorf("literal length %d exceed destination at dst pos %d", length, len(dst))
+				return nil, 0, fmt.Errorf("literal length %d exceed destination at dst pos %d", length, len(dst))
 			}
 
 			// Get input from source
 			var input []byte
 			input, ok = readN(length)
 			if !ok {
-				return nil, fmt.Errorf("literal length %d exceed source at dst pos %d", length, len(dst))
+				return nil, 0, fmt.Errorf("literal length %d exceed source at dst pos %d", length, len(dst))
 			}
 			dst = append(dst, input...)
 			continue
@@ -212,14 +222,14 @@ func DecodeBlock(src []byte) (dst []byte, err error) {
 			length = value & 15
 			offset, ok = readOne()
 			if !ok {
-				return nil, fmt.Errorf("copy 1: unable to read offset at dst pos %d", len(dst))
+				return nil, 0, fmt.Errorf("copy 1: unable to read offset at dst pos %d", len(dst))
 			}
 			// Combine offset part of value with 8 bytes read.
 			offset = offset<<2 | (value >> 4)
 			if length == 15 {
 				length, ok = readOne()
 				if !ok {
-					return nil, fmt.Errorf("copy 1: unable to read length at dst pos %d", len(dst))
+					return nil, 0, fmt.Errorf("copy 1: unable to read length at dst pos %d", len(dst))
 				}
 				length += 18
 			} else {
@@ -234,7 +244,7 @@ func DecodeBlock(src []byte) (dst []byte, err error) {
 			// Read offset
 			offset, ok = readTwo()
 			if !ok {
-				return nil, fmt.Errorf("copy 2: unable to read offset at dst pos %d", len(dst))
+				return nil, 0, fmt.Errorf("copy 2: unable to read offset at dst pos %d", len(dst))
 			}
 
 			// Resolve length
@@ -245,21 +255,21 @@ func DecodeBlock(src []byte) (dst []byte, err error) {
 				// 1 byte + 64
 				length, ok = readOne()
 				if !ok {
-					return nil, fmt.Errorf("copy 2.61: unable to read length at dst pos %d", len(dst))
+					return nil, 0, fmt.Errorf("copy 2.61: unable to read length at dst pos %d", len(dst))
 				}
 				length += 64
 			case value == 62:
 				// 2 bytes + 64
 				length, ok = readTwo()
 				if !ok {
-					return nil, fmt.Errorf("copy 2.62: unable to read length at dst pos %d", len(dst))
+					return nil, 0, fmt.Errorf("copy 2.62: unable to read length at dst pos %d", len(dst))
 				}
 				length += 64
 			case value == 63:
 				// 3 bytes + 64
 				length, ok = readThree()
 				if !ok {
-					return nil, fmt.Errorf("copy 2.63: unable to read length at dst pos %d", len(dst))
+					return nil, 0, fmt.Errorf("copy 2.63: unable to read length at dst pos %d", len(dst))
 				}
 				length += 64
 			}
@@ -280,7 +290,7 @@ func DecodeBlock(src []byte) (dst []byte, err error) {
 				// Fused copy2, length 4 -> 11.
 				offset, ok = readTwo()
 				if !ok {
-					return nil, fmt.Errorf("copy 2, fused: unable to read offset at dst pos %d", len(dst))
+					return nil, 0, fmt.Errorf("copy 2, fused: unable to read offset at dst pos %d", len(dst))
 				}
 
 				// Extract length
@@ -296,7 +306,7 @@ func DecodeBlock(src []byte) (dst []byte, err error) {
 				// Read rest of value.
 				v2, ok := readThree()
 				if !ok {
-					return nil, fmt.Errorf("copy 3: unable to read value at dst pos %d", len(dst))
+					return nil, 0, fmt.Errorf("copy 3: unable to read value at dst pos %d", len(dst))
 				}
 				// Merge top half in, so we have entire value
 				value = value | v2<<6
@@ -314,19 +324,19 @@ func DecodeBlock(src []byte) (dst []byte, err error) {
 				case value == 61:
 					length, ok = readOne()
 					if !ok {
-						return nil, fmt.Errorf("copy 3.29: unable to read length at dst pos %d", len(dst))
+						return nil, 0, fmt.Errorf("copy 3.29: unable to read length at dst pos %d", len(dst))
 					}
 					length += 64
 				case value == 62:
 					length, ok = readTwo()
 					if !ok {
-						return nil, fmt.Errorf("copy 3.30: unable to read length at dst pos %d", len(dst))
+						return nil, 0, fmt.Errorf("copy 3.30: unable to read length at dst pos %d", len(dst))
 					}
 					length += 64
 				case value == 63:
 					length, ok = readThree()
 					if !ok {
-						return nil, fmt.Errorf("copy 3.31: unable to read length at dst pos %d", len(dst))
+						return nil, 0, fmt.Errorf("copy 3.31: unable to read length at dst pos %d", len(dst))
 					}
 					length += 64
 				}
@@ -336,11 +346,11 @@ func DecodeBlock(src []byte) (dst []byte, err error) {
 				// Read literals from input.
 				input, ok := readN(litLen)
 				if !ok {
-					return nil, fmt.Errorf("copy 3: unable to read extra literals at dst pos %d", len(dst))
+					return nil, 0, fmt.Errorf("copy 3: unable to read extra literals at dst pos %d", len(dst))
 				}
 				// Add them before copy.
 				if !checkDstSize(litLen) {
-					return nil, fmt.Errorf("copy 3: extra literal output size exceeded at dst pos %d", len(dst))
+					return nil, 0, fmt.Errorf("copy 3: extra literal output size exceeded at dst pos %d", len(dst))
 				}
 				dst = append(dst, input...)
 			}
@@ -352,10 +362,10 @@ func DecodeBlock(src []byte) (dst []byte, err error) {
 
 		// All paths have filled length & offset - execute copy.
 		if !checkDstSize(length) {
-			return nil, fmt.Errorf("copy length %d exceeds dst size at dst pos %d", length, len(dst))
+			return nil, 0, fmt.Errorf("copy length %d exceeds dst size at dst pos %d", length, len(dst))
 		}
 		if offset > uint32(len(dst)) {
-			return nil, fmt.Errorf("copy offset %d exceeds dst size %d", offset, len(dst))
+			return nil, 0, fmt.Errorf("copy offset %d exceeds dst size %d", offset, len(dst))
 		}
 
 		// Calculate input position
@@ -367,7 +377,7 @@ func DecodeBlock(src []byte) (dst []byte, err error) {
 		}
 	}
 	if len(dst) != wantSize {
-		return nil, fmt.Errorf("mismatching output size, got %d, want %d", len(dst), wantSize)
+		return nil, 0, fmt.Errorf("mismatching output size, got %d, want %d", len(dst), wantSize)
 	}
-	return dst, nil
+	return dst, offset, nil
 }
@@ -0,0 +1,369 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reference
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// WindowTooSmallError is returned by DecodeBlockTo/DecodeBlockToFit when the
+// caller-supplied window isn't large enough to hold every back-reference
+// offset used by the block (or, for DecodeBlockToFit, the whole
+// decompressed block).
+type WindowTooSmallError struct {
+	Need int // Bytes of window that would have been required.
+	Have int // Bytes of window actually supplied.
+}
+
+func (e *WindowTooSmallError) Error() string {
+	return fmt.Sprintf("minlz: window of %d bytes too small, need at least %d", e.Have, e.Need)
+}
+
+// copyChunkSize bounds how many bytes a single copy's output is built up in
+// scratch memory before being flushed to dst, so decoding a single very
+// long copy never allocates more than this regardless of its length.
+const copyChunkSize = 4096
+
+// DecodeBlockTo decodes src, a single self-contained MinLZ block, writing
+// the decompressed output to dst as it's produced rather than buffering the
+// whole result in memory. window is used as a ring buffer holding the most
+// recently decoded bytes, which is all a copy tag ever needs to resolve a
+// back-reference; it must be at least as large as the largest back-reference
+// offset the block uses (up to 65535+64 for a copy2 tag, or 65535+2^21 for a
+// copy3 tag) or decoding fails with a *WindowTooSmallError once such an
+// offset is actually encountered.
+//
+// This lets memory-constrained callers (e.g. an embedded target) decode
+// blocks far larger than they could hold fully in memory, at the cost of
+// not knowing up front whether window is big enough - see DecodeBlockToFit
+// for that guarantee.
+func DecodeBlockTo(dst io.Writer, src []byte, window []byte) error {
+	return decodeBlockTo(dst, src, window, false)
+}
+
+// DecodeBlockToFit is like DecodeBlockTo, but first checks the block's
+// declared uncompressed size against len(window) and fails immediately with
+// a *WindowTooSmallError if the block couldn't possibly fit, rather than
+// discovering a too-small window partway through decoding. Use this when a
+// caller needs a hard guarantee before committing to decode a block.
+func DecodeBlockToFit(dst io.Writer, src []byte, window []byte) error {
+	return decodeBlockTo(dst, src, window, true)
+}
+
+func decodeBlockTo(dst io.Writer, src []byte, window []byte, requireFit bool) error {
+	if len(window) == 0 {
+		return errors.New("minlz: DecodeBlockTo requires a non-empty window")
+	}
+	if len(src) == 0 {
+		return errors.New("src length is zero")
+	}
+	if src[0] != 0 {
+		return errors.New("first byte is not 0")
+	}
+	if len(src) == 1 {
+		return nil
+	}
+	src = src[1:]
+
+	var wantSize int
+	for i := uint(0); ; i += 7 {
+		if i == 7*10 {
+			return fmt.Errorf("invalid destination size")
+		}
+		if len(src) == 0 {
+			return errors.New("unable to read length")
+		}
+		v := src[0]
+		wantSize |= int(v&0x7f) << i
+		if wantSize > maxBlockSize {
+			return fmt.Errorf("invalid destination size")
+		}
+		src = src[1:]
+		if v&0x80 == 0 {
+			break
+		}
+	}
+	if wantSize < 0 || wantSize > maxBlockSize {
+		return fmt.Errorf("invalid destination size %d", wantSize)
+	}
+	if requireFit && wantSize > len(window) {
+		return &WindowTooSmallError{Need: wantSize, Have: len(window)}
+	}
+	if wantSize == 0 {
+		_, err := dst.Write(src)
+		return err
+	}
+	if wantSize < len(src) {
+		return fmt.Errorf("decompressed smaller than compressed size %d", wantSize)
+	}
+
+	ring := window
+	ringLen := len(ring)
+	pos := 0 // total decoded bytes so far
+
+	checkSize := func(n uint32) bool {
+		return n < maxBlockSize && pos+int(n) <= wantSize
+	}
+
+	emitLiteral := func(b []byte) error {
+		for i, c := range b {
+			ring[(pos+i)%ringLen] = c
+		}
+		pos += len(b)
+		_, err := dst.Write(b)
+		return err
+	}
+
+	// emitCopy streams length bytes, read one at a time from offset bytes
+	// behind the current position, through dst in chunks of at most
+	// copyChunkSize - handling an offset smaller than length (a
+	// self-overlapping, repeating copy) the same way DecodeBlock's
+	// sequential append does, since every byte is committed to the ring
+	// immediately after being produced.
+	emitCopy := func(offset uint32, length uint32) error {
+		if int(offset) > pos {
+			return fmt.Errorf("copy offset %d exceeds decoded size %d", offset, pos)
+		}
+		if int(offset) > ringLen {
+			return &WindowTooSmallError{Need: int(offset), Have: ringLen}
+		}
+		base := pos
+		chunk := make([]byte, 0, copyChunkSize)
+		done := 0
+		for done < int(length) {
+			n := int(length) - done
+			if n > copyChunkSize {
+				n = copyChunkSize
+			}
+			chunk = chunk[:n]
+			for i := 0; i < n; i++ {
+				b := ring[(base-int(offset)+done+i)%ringLen]
+				ring[(base+done+i)%ringLen] = b
+				chunk[i] = b
+			}
+			if _, err := dst.Write(chunk); err != nil {
+				return err
+			}
+			done += n
+		}
+		pos = base + int(length)
+		return nil
+	}
+
+	readOne := func() (v uint32, ok bool) {
+		if len(src) >= 1 {
+			v = uint32(src[0])
+			src = src[1:]
+			return v, true
+		}
+		return 0, false
+	}
+	readTwo := func() (v uint32, ok bool) {
+		if len(src) >= 2 {
+			v = uint32(src[0]) | uint32(src[1])<<8
+			src = src[2:]
+			return v, true
+		}
+		return 0, false
+	}
+	readThree := func() (v uint32, ok bool) {
+		if len(src) >= 3 {
+			v = uint32(src[0]) | uint32(src[1])<<8 | uint32(src[2])<<16
+			src = src[3:]
+			return v, true
+		}
+		return 0, false
+	}
+	readN := func(n uint32) (v []byte, ok bool) {
+		if uint32(len(src)) >= n {
+			v = src[:n]
+			src = src[n:]
+			return v, true
+		}
+		return nil, false
+	}
+
+	var offset = uint32(1)
+
+	for len(src) > 0 {
+		v, ok := readOne()
+		if !ok {
+			break
+		}
+		tag := v & 3
+		value := v >> 2
+		var length uint32
+		switch tag {
+		case 0: // Literal/repeat tag.
+			isRepeat := value&1 != 0
+			value = value >> 1
+			switch {
+			case value < 29:
+				length = value + 1
+			case value == 29:
+				length, ok = readOne()
+				if !ok {
+					return fmt.Errorf("lit tag 29: unable to read length at pos %d", pos)
+				}
+				length += 30
+			case value == 30:
+				length, ok = readTwo()
+				if !ok {
+					return fmt.Errorf("lit tag 30: unable to read length at pos %d", pos)
+				}
+				length += 30
+			case value == 31:
+				length, ok = readThree()
+				if !ok {
+					return fmt.Errorf("lit tag 31: unable to read length at pos %d", pos)
+				}
+				length += 30
+			}
+
+			if isRepeat {
+				break
+			}
+
+			if !checkSize(length) {
+				return fmt.Errorf("literal length %d exceed destination at pos %d", length, pos)
+			}
+			input, ok := readN(length)
+			if !ok {
+				return fmt.Errorf("literal length %d exceed source at pos %d", length, pos)
+			}
+			if err := emitLiteral(input); err != nil {
+				return err
+			}
+			continue
+
+		case 1: // Copy with 1 byte extra offset.
+			length = value & 15
+			offset, ok = readOne()
+			if !ok {
+				return fmt.Errorf("copy 1: unable to read offset at pos %d", pos)
+			}
+			offset = offset<<2 | (value >> 4)
+			if length == 15 {
+				length, ok = readOne()
+				if !ok {
+					return fmt.Errorf("copy 1: unable to read length at pos %d", pos)
+				}
+				length += 18
+			} else {
+				length += 4
+			}
+			offset++
+
+		case 2: // Copy with 2 byte offset.
+			offset, ok = readTwo()
+			if !ok {
+				return fmt.Errorf("copy 2: unable to read offset at pos %d", pos)
+			}
+			switch {
+			case value <= 60:
+				length = value + 4
+			case value == 61:
+				length, ok = readOne()
+				if !ok {
+					return fmt.Errorf("copy 2.61: unable to read length at pos %d", pos)
+				}
+				length += 64
+			case value == 62:
+				length, ok = readTwo()
+				if !ok {
+					return fmt.Errorf("copy 2.62: unable to read length at pos %d", pos)
+				}
+				length += 64
+			case value == 63:
+				length, ok = readThree()
+				if !ok {
+					return fmt.Errorf("copy 2.63: unable to read length at pos %d", pos)
+				}
+				length += 64
+			}
+			offset += 64
+
+		case 3: // Fused copy2 or copy3.
+			isCopy3 := value&1 == 1
+			litLen := value >> 1 & 3
+
+			if !isCopy3 {
+				offset, ok = readTwo()
+				if !ok {
+					return fmt.Errorf("copy 2, fused: unable to read offset at pos %d", pos)
+				}
+				length = (value >> 3) + 4
+				litLen++
+				offset += 64
+			} else {
+				v2, ok := readThree()
+				if !ok {
+					return fmt.Errorf("copy 3: unable to read value at pos %d", pos)
+				}
+				value = value | v2<<6
+				offset = (value >> 9) + 65536
+				value = (value >> 3) & 63
+				switch {
+				case value < 61:
+					length = value + 4
+				case value == 61:
+					length, ok = readOne()
+					if !ok {
+						return fmt.Errorf("copy 3.29: unable to read length at pos %d", pos)
+					}
+					length += 64
+				case value == 62:
+					length, ok = readTwo()
+					if !ok {
+						return fmt.Errorf("copy 3.30: unable to read length at pos %d", pos)
+					}
+					length += 64
+				case value == 63:
+					length, ok = readThree()
+					if !ok {
+						return fmt.Errorf("copy 3.31: unable to read length at pos %d", pos)
+					}
+					length += 64
+				}
+			}
+
+			if litLen > 0 {
+				input, ok := readN(litLen)
+				if !ok {
+					return fmt.Errorf("copy 3: unable to read extra literals at pos %d", pos)
+				}
+				if !checkSize(litLen) {
+					return fmt.Errorf("copy 3: extra literal output size exceeded at pos %d", pos)
+				}
+				if err := emitLiteral(input); err != nil {
+					return err
+				}
+			}
+		}
+
+		if !checkSize(length) {
+			return fmt.Errorf("copy length %d exceeds dst size at pos %d", length, pos)
+		}
+		if err := emitCopy(offset, length); err != nil {
+			return err
+		}
+	}
+	if pos != wantSize {
+		return fmt.Errorf("mismatching output size, got %d, want %d", pos, wantSize)
+	}
+	return nil
+}
@@ -48,6 +48,231 @@ func EncodeBlock(src []byte) ([]byte, error) {
 	return encodeUncompressed(dst[:0], src), nil
 }
 
+// EncodeBlockFrom is EncodeBlock, parameterized by the repeat offset to
+// start the block with, and returning the offset left active at the end of
+// the block (1 if the block was emitted uncompressed). EncodeBlocks uses
+// this to carry a repeat offset across block boundaries.
+func EncodeBlockFrom(src []byte, initRepeat int) ([]byte, int, error) {
+	n := MaxEncodedLen(len(src))
+	dst := make([]byte, 0, n)
+
+	if len(src) <= 16 {
+		return encodeUncompressed(dst, src), 1, nil
+	}
+
+	dst = append(dst, 0)
+	dst = binary.AppendUvarint(dst, uint64(len(src)))
+	compressed, repeat := encodeBlockFrom(dst, src, initRepeat)
+
+	if compressed != nil {
+		return compressed, repeat, nil
+	}
+	return encodeUncompressed(dst[:0], src), 1, nil
+}
+
+// EncodeBlockBetter is a reference implementation of a slower, better
+// compressing MinLZ block encoder. Unlike EncodeBlock, which only looks up
+// a single 4-byte hash table, it keeps a second table hashed on longer runs
+// of input bytes, so it can find long matches a 4-byte-only search would
+// miss the start of, and extends every candidate backward as well as
+// forward. It's roughly twice as slow as EncodeBlock for a few percent
+// better compression; EncodeBlock remains the default.
+func EncodeBlockBetter(src []byte) ([]byte, error) {
+	n := MaxEncodedLen(len(src))
+	dst := make([]byte, 0, n)
+
+	if len(src) <= 16 {
+		return encodeUncompressed(dst, src), nil
+	}
+
+	dst = append(dst, 0)
+	dst = binary.AppendUvarint(dst, uint64(len(src)))
+	compressed := encodeBlockBetter(dst, src)
+
+	if compressed != nil {
+		return compressed, nil
+	}
+	return encodeUncompressed(dst[:0], src), nil
+}
+
+// encodeBlockBetter is EncodeBlockBetter's body. It assumes the same
+// preconditions as encodeBlock.
+func encodeBlockBetter(dst, src []byte) (res []byte) {
+	const (
+		shortTableBits = 17
+		shortTableSize = 1 << shortTableBits
+		longTableBits  = 17
+		longTableSize  = 1 << longTableBits
+		inputMargin    = 8
+	)
+
+	// Two lookup tables on the stack: shortTable finds short matches the
+	// way encodeBlockFrom's single table does; longTable is indexed on 8
+	// bytes instead of 4, so it only holds candidates for longer runs,
+	// letting it find the start of a long match that hash4 would only
+	// notice a few bytes in.
+	var shortTable [shortTableSize]uint32
+	var longTable [longTableSize]uint32
+
+	sLimit := len(src) - inputMargin
+	if sLimit < 0 {
+		return nil
+	}
+	dstLimit := len(src) + len(dst) - 11
+
+	nextEmit := 0
+	s := 1
+	repeat := 1
+
+	for {
+		candidateShort := 0
+		candidateLong := 0
+		var offset, length int
+
+		for {
+			if s > sLimit {
+				goto emitRemainder
+			}
+			cv := binary.LittleEndian.Uint32(src[s:])
+			hs := hash4(cv, shortTableBits)
+			candidateShort = int(shortTable[hs])
+			shortTable[hs] = uint32(s)
+
+			lv := binary.LittleEndian.Uint64(src[s:])
+			hl := hash8(lv, longTableBits)
+			candidateLong = int(longTable[hl])
+			longTable[hl] = uint32(s)
+
+			minSrcPos := s - (2 << 20) - 65535
+
+			// candidateLong < s excludes the table entries the
+			// "extra positions" insert below plants ahead of the scan:
+			// without it, the scan could reach one of those positions
+			// and match against itself, an offset-0 "match".
+			shortOK := candidateShort >= minSrcPos && cv == binary.LittleEndian.Uint32(src[candidateShort:])
+			longOK := candidateLong >= minSrcPos && candidateLong < s && lv == binary.LittleEndian.Uint64(src[candidateLong:])
+
+			if shortOK || longOK {
+				base, cand := s, candidateShort
+				if longOK {
+					// An 8-byte hash hit is already at least as long a
+					// match as a 4-byte one, and typically longer; prefer
+					// it unless the short table's candidate turns out,
+					// after extension below, to win on length or offset.
+					cand = candidateLong
+				}
+				length, offset, base = extendMatch(src, base, cand, nextEmit)
+
+				// If both tables hit, also try the short candidate and
+				// keep whichever is better, biasing to the smaller offset
+				// on a tie.
+				if shortOK && longOK && candidateShort != candidateLong {
+					length2, offset2, base2 := extendMatch(src, s, candidateShort, nextEmit)
+					if length2 > length || (length2 == length && offset2 < offset) {
+						length, offset, base = length2, offset2, base2
+					}
+				}
+				s = base + length
+				goto foundMatch
+			}
+
+			s++
+		}
+
+	foundMatch:
+		base := s - length
+
+		if nextEmit != base {
+			literals := src[nextEmit:base]
+			canFuse := (len(literals) <= 3 || (offset <= 65535+64 && len(literals) <= 4)) && offset >= 64
+			if canFuse {
+				if offset <= 65535+64 {
+					dst = emitCopyLits2(dst, literals, offset, length)
+				} else {
+					dst = emitCopy3(dst, offset, length, literals)
+				}
+				length = 0
+			} else {
+				if len(dst)+len(literals) > dstLimit {
+					return nil
+				}
+				dst = emitLiterals(dst, literals)
+			}
+		}
+		if length > 0 {
+			if offset == repeat {
+				dst = emitRepeat(dst, length)
+			} else if offset <= 1024 {
+				dst = emitCopy1(dst, offset, length)
+			} else if offset <= 65535+64 {
+				dst = emitCopy2(dst, offset, length)
+			} else {
+				dst = emitCopy3(dst, offset, length, nil)
+			}
+		}
+		repeat = offset
+		nextEmit = s
+
+		if s > sLimit {
+			goto emitRemainder
+		}
+		if len(dst) > dstLimit {
+			return nil
+		}
+
+		// Insert every position from base+1 up to the match's end into
+		// both tables, then a couple of extra positions past it into the
+		// long table, so an overlapping long match starting just after
+		// this one is still found.
+		for i := base + 1; i < s; i++ {
+			if i+8 > len(src) {
+				break
+			}
+			shortTable[hash4(binary.LittleEndian.Uint32(src[i:]), shortTableBits)] = uint32(i)
+			longTable[hash8(binary.LittleEndian.Uint64(src[i:]), longTableBits)] = uint32(i)
+		}
+		// s itself is left alone: it's where the next search starts, and
+		// inserting it here would make that search find itself.
+		for i := s + 1; i < s+3 && i+8 <= len(src); i++ {
+			longTable[hash8(binary.LittleEndian.Uint64(src[i:]), longTableBits)] = uint32(i)
+		}
+	}
+
+emitRemainder:
+	if nextEmit < len(src) {
+		if len(dst)+len(src)-nextEmit > dstLimit {
+			return nil
+		}
+		dst = emitLiterals(dst, src[nextEmit:])
+	}
+	return dst
+}
+
+// extendMatch extends a match candidate at src[base:] / src[cand:] both
+// forward and backward, returning the resulting length, offset and
+// (possibly earlier) base. Backward extension stops at nextEmit, the
+// start of the literals pending before this match, since bytes before
+// that have already been emitted by an earlier match.
+func extendMatch(src []byte, base, cand, nextEmit int) (length, offset, newBase int) {
+	s, c := base, cand
+	for s < len(src) && src[s] == src[c] {
+		s++
+		c++
+	}
+	for base > nextEmit && cand > 0 && src[base-1] == src[cand-1] {
+		base--
+		cand--
+	}
+	return s - base, base - cand, base
+}
+
+// hash8 returns the hash of the lowest 8 bytes of u to fit in a hash table
+// with h bits, for use by the long table in encodeBlockBetter.
+func hash8(u uint64, h uint8) uint32 {
+	const prime8bytes = 0x9E3779B185EBCA87
+	return uint32((u * prime8bytes) >> ((64 - h) & 63))
+}
+
 // encodeUncompressed will append src to dst as uncompressed data and return it.
 func encodeUncompressed(dst, src []byte) []byte {
 	// This is a valid method to represent a length 0 payload.
@@ -82,6 +307,16 @@ func MaxEncodedLen(srcLen int) int {
 //	len(dst) >= MaxEncodedLen(len(src)) &&
 //	minNonLiteralBlockSize <= len(src) && len(src) <= maxBlockSize
 func encodeBlock(dst, src []byte) (res []byte) {
+	res, _ = encodeBlockFrom(dst, src, 1)
+	return res
+}
+
+// encodeBlockFrom is encodeBlock's body, parameterized by the repeat
+// offset to start with and returning the offset left active at the end of
+// the block (0 if the block was incompressible and nil was returned).
+// EncodeBlocks uses this to carry the previous block's final offset
+// forward as the next block's initial repeat offset.
+func encodeBlockFrom(dst, src []byte, initRepeat int) (res []byte, finalRepeat int) {
 	// Initialize the hash table.
 	const (
 		tableBits    = 16
@@ -113,7 +348,7 @@ func encodeBlock(dst, src []byte) (res []byte) {
 	// bytes to copy, so we start looking for hash matches at s == 1.
 	s := 1
 
-	repeat := 1
+	repeat := initRepeat
 	if debug {
 		fmt.Println("encodeBlockGo: Starting encode")
 	}
@@ -197,7 +432,7 @@ func encodeBlock(dst, src []byte) (res []byte) {
 				// Bail if we will exceed the maximum size.
 				// We will not exceed dstLimit with the other encodings.
 				if len(dst)+len(literals) > dstLimit {
-					return nil
+					return nil, 0
 				}
 				dst = emitLiterals(dst, literals)
 				if debug {
@@ -229,7 +464,7 @@ func encodeBlock(dst, src []byte) (res []byte) {
 		}
 		if len(dst) > dstLimit {
 			// Do we have space for more, if not bail.
-			return nil
+			return nil, 0
 		}
 
 		// Index from base+1 to the end of match.
@@ -246,11 +481,11 @@ emitRemainder:
 	if nextEmit < len(src) {
 		// Bail if we exceed the maximum size.
 		if len(dst)+len(src)-nextEmit > dstLimit {
-			return nil
+			return nil, 0
 		}
 		dst = emitLiterals(dst, src[nextEmit:])
 	}
-	return dst
+	return dst, repeat
 }
 
 // hash4 returns the hash of the lowest 4 bytes of u to fit in a hash table with h bits.
@@ -0,0 +1,187 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reference
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// encoderPosBits is the number of bits of an Encoder table slot spent on
+// the source position; the rest tag the slot with the generation it was
+// last written in. maxBlockSize fits in 23 bits exactly.
+const (
+	encoderPosBits   = 23
+	encoderPosMask   = 1<<encoderPosBits - 1
+	encoderMaxGen    = 1<<(32-encoderPosBits) - 1
+	encoderTableBits = 16
+	encoderTableSize = 1 << encoderTableBits
+)
+
+// Encoder holds the hash table EncodeBlock would otherwise allocate and
+// zero-fill on every call. Reusing an Encoder across many small blocks
+// avoids that per-call 256 KiB zero-fill: instead of clearing the table,
+// AppendBlock tags every slot it writes with a generation counter that it
+// bumps once per call, and treats a slot whose tag doesn't match the
+// current generation as empty. The table is only actually zeroed when the
+// generation counter wraps around. The zero value is ready to use.
+type Encoder struct {
+	table [encoderTableSize]uint32
+	gen   uint32
+}
+
+// AppendBlock appends the MinLZ encoding of src to dst and returns the
+// extended buffer, reusing e's hash table instead of allocating a new one.
+// It otherwise behaves like EncodeBlock.
+func (e *Encoder) AppendBlock(dst, src []byte) ([]byte, error) {
+	if MaxEncodedLen(len(src)) < 0 {
+		return nil, fmt.Errorf("reference: source too large to encode, %d > %d", len(src), maxBlockSize)
+	}
+
+	if len(src) <= 16 {
+		return encodeUncompressed(dst, src), nil
+	}
+
+	base := len(dst)
+	dst = append(dst, 0)
+	dst = binary.AppendUvarint(dst, uint64(len(src)))
+	compressed := e.encodeBlock(dst, src)
+	if compressed != nil {
+		return compressed, nil
+	}
+	return encodeUncompressed(dst[:base], src), nil
+}
+
+// lookup returns the position last inserted at hash during the current
+// generation, or ok == false if the slot is empty or stale.
+func (e *Encoder) lookup(hash uint32) (pos int, ok bool) {
+	v := e.table[hash]
+	if v>>encoderPosBits != e.gen {
+		return 0, false
+	}
+	return int(v & encoderPosMask), true
+}
+
+// insert tags pos with the current generation and stores it at hash.
+func (e *Encoder) insert(hash uint32, pos int) {
+	e.table[hash] = uint32(pos) | e.gen<<encoderPosBits
+}
+
+// encodeBlock is AppendBlock's body, parameterized by e so it can reuse e's
+// table across calls. It's encodeBlockFrom with every table read/write
+// routed through e's generation-tagged slots instead of a fresh table.
+func (e *Encoder) encodeBlock(dst, src []byte) (res []byte) {
+	const inputMargin = 4
+
+	e.gen++
+	if e.gen > encoderMaxGen {
+		e.table = [encoderTableSize]uint32{}
+		e.gen = 1
+	}
+
+	sLimit := len(src) - inputMargin
+	dstLimit := len(src) + len(dst) - 11
+
+	nextEmit := 0
+	s := 1
+	repeat := 1
+
+	for {
+		candidate := 0
+		minSrcPos := s - (2 << 20) - 65535
+
+		for {
+			if s > sLimit {
+				goto emitRemainder
+			}
+			cv := binary.LittleEndian.Uint32(src[s:])
+			hash := hash4(cv, encoderTableBits)
+			pos, ok := e.lookup(hash)
+			e.insert(hash, s)
+			if ok {
+				candidate = pos
+				if candidate >= minSrcPos && cv == binary.LittleEndian.Uint32(src[candidate:]) {
+					break
+				}
+			}
+			s++
+			minSrcPos++
+		}
+
+		base := s
+		offset := s - candidate
+
+		candidate += 4
+		s += 4
+		for s < len(src) && src[s] == src[candidate] {
+			candidate++
+			s++
+		}
+		length := s - base
+
+		if nextEmit != base {
+			literals := src[nextEmit:base]
+			canFuse := (len(literals) <= 3 || (offset <= 65535+64 && len(literals) <= 4)) && offset >= 64
+			if canFuse {
+				if offset <= 65535+64 {
+					dst = emitCopyLits2(dst, literals, offset, length)
+				} else {
+					dst = emitCopy3(dst, offset, length, literals)
+				}
+				length = 0
+			} else {
+				if len(dst)+len(literals) > dstLimit {
+					return nil
+				}
+				dst = emitLiterals(dst, literals)
+			}
+		}
+		if length > 0 {
+			if offset == repeat {
+				dst = emitRepeat(dst, length)
+			} else if offset <= 1024 {
+				dst = emitCopy1(dst, offset, length)
+			} else if offset <= 65535+64 {
+				dst = emitCopy2(dst, offset, length)
+			} else {
+				dst = emitCopy3(dst, offset, length, nil)
+			}
+		}
+		repeat = offset
+		nextEmit = s
+
+		if s > sLimit {
+			goto emitRemainder
+		}
+		if len(dst) > dstLimit {
+			return nil
+		}
+
+		base++
+		for base < s {
+			e.insert(hash4(binary.LittleEndian.Uint32(src[base:]), encoderTableBits), base)
+			base++
+		}
+	}
+
+emitRemainder:
+	if nextEmit < len(src) {
+		if len(dst)+len(src)-nextEmit > dstLimit {
+			return nil
+		}
+		dst = emitLiterals(dst, src[nextEmit:])
+	}
+	return dst
+}
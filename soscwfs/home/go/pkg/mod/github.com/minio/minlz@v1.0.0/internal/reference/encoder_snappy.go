@@ -0,0 +1,166 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reference
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// EncodeBlockSnappy is a reference implementation of a Snappy block encoder:
+// it reuses EncodeBlock's hash table match search unchanged, but emits
+// Snappy-format tags instead of MinLZ ones, and prepends the uvarint
+// decompressed length the Snappy block API uses in place of MinLZ's marker
+// byte and length. The result is byte-for-byte decodable by any stock
+// Snappy block consumer, such as github.com/golang/snappy's Decode.
+func EncodeBlockSnappy(src []byte) ([]byte, error) {
+	n := maxSnappyEncodedLen(len(src))
+	if n < 0 {
+		return nil, errors.New("reference: source too large to encode")
+	}
+	dst := make([]byte, 0, n)
+	dst = binary.AppendUvarint(dst, uint64(len(src)))
+	return encodeBlockSnappy(dst, src), nil
+}
+
+// maxSnappyEncodedLen returns the maximum length of a Snappy-encoded block,
+// given its uncompressed length, including the leading uvarint length.
+func maxSnappyEncodedLen(srcLen int) int {
+	if srcLen < 0 || srcLen > maxBlockSize {
+		return -1
+	}
+	// The same bound as github.com/golang/snappy's MaxEncodedLen
+	// (32 + n + n/6), plus the leading uvarint length.
+	return 32 + srcLen + srcLen/6 + binary.MaxVarintLen64
+}
+
+// encodeBlockSnappy appends src to dst as a Snappy tag stream, using the
+// same hash4 single-table search as encodeBlockFrom. Unlike encodeBlockFrom,
+// it always returns a usable result: Snappy's block format has no separate
+// uncompressed representation, so incompressible input is simply emitted as
+// one long literal run.
+func encodeBlockSnappy(dst, src []byte) []byte {
+	const (
+		tableBits    = 16
+		maxTableSize = 1 << tableBits
+		inputMargin  = 4
+	)
+
+	if len(src) < 5 {
+		return emitLiteralSnappy(dst, src)
+	}
+
+	var table [maxTableSize]uint32
+	sLimit := len(src) - inputMargin
+	nextEmit := 0
+	s := 0
+
+	for {
+		candidate := 0
+		for {
+			if s > sLimit {
+				goto emitRemainder
+			}
+			cv := binary.LittleEndian.Uint32(src[s:])
+			hash := hash4(cv, tableBits)
+			candidate = int(table[hash])
+			table[hash] = uint32(s)
+			if candidate != s && cv == binary.LittleEndian.Uint32(src[candidate:]) {
+				break
+			}
+			s++
+		}
+
+		if nextEmit != s {
+			dst = emitLiteralSnappy(dst, src[nextEmit:s])
+		}
+
+		base := s
+		offset := s - candidate
+		candidate += 4
+		s += 4
+		for s < len(src) && src[s] == src[candidate] {
+			candidate++
+			s++
+		}
+		dst = emitCopySnappy(dst, offset, s-base)
+		nextEmit = s
+
+		if s > sLimit {
+			goto emitRemainder
+		}
+
+		// Index from base+1 to the end of the match.
+		base++
+		for base < s {
+			table[hash4(binary.LittleEndian.Uint32(src[base:]), tableBits)] = uint32(base)
+			base++
+		}
+	}
+
+emitRemainder:
+	if nextEmit < len(src) {
+		dst = emitLiteralSnappy(dst, src[nextEmit:])
+	}
+	return dst
+}
+
+// emitLiteralSnappy appends a Snappy literal tag and lit itself to dst.
+func emitLiteralSnappy(dst, lit []byte) []byte {
+	if len(lit) == 0 {
+		return dst
+	}
+	n := len(lit) - 1
+	switch {
+	case n < 60:
+		dst = append(dst, byte(n)<<2)
+	case n < 1<<8:
+		dst = append(dst, 60<<2, byte(n))
+	case n < 1<<16:
+		dst = append(dst, 61<<2, byte(n), byte(n>>8))
+	case n < 1<<24:
+		dst = append(dst, 62<<2, byte(n), byte(n>>8), byte(n>>16))
+	default:
+		dst = append(dst, 63<<2, byte(n), byte(n>>8), byte(n>>16), byte(n>>24))
+	}
+	return append(dst, lit...)
+}
+
+// emitCopySnappy appends one or more Snappy copy tags encoding a match of
+// the given offset and length. A single copy tag can carry at most 64
+// bytes, so longer matches are split across several tags; the offset is
+// carried in 2 bytes if it fits, and in 4 bytes (the copy4 tag) otherwise.
+func emitCopySnappy(dst []byte, offset, length int) []byte {
+	if offset < 2048 && length >= 4 && length <= 11 {
+		dst = append(dst, byte(length-4)<<2|byte(offset>>8)<<5|1, byte(offset))
+		return dst
+	}
+	for length > 0 {
+		chunk := length
+		if chunk > 64 {
+			chunk = 64
+		}
+		length -= chunk
+		if offset <= 65535 {
+			dst = append(dst, byte((chunk-1)<<2|2), byte(offset), byte(offset>>8))
+		} else {
+			var off [4]byte
+			binary.LittleEndian.PutUint32(off[:], uint32(offset))
+			dst = append(dst, byte((chunk-1)<<2|3))
+			dst = append(dst, off[:]...)
+		}
+	}
+	return dst
+}
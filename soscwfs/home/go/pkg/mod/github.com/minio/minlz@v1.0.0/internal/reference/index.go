@@ -16,9 +16,7 @@ package reference
 
 import (
 	"bytes"
-	"encoding/binary"
 	"errors"
-	"fmt"
 	"io"
 )
 
@@ -63,129 +61,34 @@ func LoadIndex(b []byte) (*Index, error) {
 }
 
 // LoadIndexAfterHeader will load and parse an index, after the stream header has been parsed.
+//
+// This is implemented on top of IndexScanner, reading every block it
+// produces into Index.Blocks; callers with very large indexes that only
+// need a handful of blocks (or just a single lookup) should use
+// NewIndexScanner directly instead, to avoid paying for the rest.
 func LoadIndexAfterHeader(b []byte) (*Index, error) {
-	var i Index
-	if !bytes.Equal(b[:len(IndexHeader)], []byte(IndexHeader)) {
-		return nil, errors.New("invalid index header")
-	}
-	b = b[len(IndexHeader):]
-
-	// Total Uncompressed Size
-	if v, n := binary.Varint(b); n <= 0 || v < 0 {
-		return nil, errors.New("unable to read uncompressed size")
-	} else {
-		i.TotalUncompressed = v
-		b = b[n:]
-	}
-
-	// Total Compressed Size (or -1)
-	if v, n := binary.Varint(b); n <= 0 {
-		return nil, errors.New("unable to read compressed size")
-	} else {
-		i.TotalCompressed = v
-		b = b[n:]
-	}
-
-	// Read Estimated Uncompressed Block Size.
-	if v, n := binary.Varint(b); n <= 0 {
-		return nil, errors.New("unable to read estimated compressed size")
-	} else {
-		if v < 0 {
-			return nil, fmt.Errorf("invalid estimated uncompressed size: %d", v)
-		}
-		i.estBlockUncomp = v
-		b = b[n:]
+	s, err := NewIndexScanner(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
 	}
-
-	var entries int
-	if v, n := binary.Varint(b); n <= 0 {
-		return nil, errors.New("unable to read entry count")
-	} else {
-		if v < 0 || v > MaxIndexEntries {
-			return nil, fmt.Errorf("invalid entry count: %d", v)
-		}
-		entries = int(v)
-		b = b[n:]
+	i := &Index{
+		TotalUncompressed: s.TotalUncompressed,
+		TotalCompressed:   s.TotalCompressed,
+		estBlockUncomp:    s.estBlockUncomp,
 	}
 	i.Blocks = make([]struct {
 		CompressedOffset   int64
 		UncompressedOffset int64
-	}, entries)
-
-	if len(b) < 1 {
-		return nil, io.ErrUnexpectedEOF
-	}
-	hasUncompressed := b[0]
-	b = b[1:]
-	if hasUncompressed&1 != hasUncompressed {
-		return nil, errors.New("invalid has uncompressed value")
-	}
-
-	// Add each uncompressed entry
-	for idx := range i.Blocks {
-		var uOff int64
-		if hasUncompressed != 0 {
-			// Load delta
-			if v, n := binary.Varint(b); n <= 0 {
-				return nil, errors.New("unable to load uncompressed delta")
-			} else {
-				uOff = v
-				b = b[n:]
-			}
-		}
-
-		if idx > 0 {
-			prev := i.Blocks[idx-1].UncompressedOffset
-			uOff += prev + (i.estBlockUncomp)
-			if uOff <= prev {
-				return nil, fmt.Errorf("new uncompressed offset %d less than previous %d", uOff, prev)
-			}
-		}
-		if uOff < 0 {
-			return nil, errors.New("negative uncompressed offset")
-		}
-		i.Blocks[idx].UncompressedOffset = uOff
-	}
-
-	// Initial compressed size estimate.
-	cPredict := i.estBlockUncomp / 2
-
-	// Add each compressed entry
-	for idx := range i.Blocks {
-		var cOff int64
-		if v, n := binary.Varint(b); n <= 0 {
-			return nil, errors.New("unable to load delta")
-		} else {
-			cOff = v
-			b = b[n:]
-		}
-
-		if idx > 0 {
-			// Update compressed size prediction, with half the error.
-			cPredictNew := cPredict + cOff/2
-
-			prev := i.Blocks[idx-1].CompressedOffset
-			cOff += prev + cPredict
-			if cOff <= prev {
-				return nil, fmt.Errorf("new compressed offset %d less than previous %d", cOff, prev)
-			}
-			cPredict = cPredictNew
-		}
-		if cOff < 0 {
-			return nil, errors.New("negative compressed offset")
-		}
-		i.Blocks[idx].CompressedOffset = cOff
-	}
-	if len(b) < 4+len(IndexTrailer) {
-		return nil, io.ErrUnexpectedEOF
+	}, 0, s.entries)
+	for s.Next() {
+		c, u := s.Block()
+		i.Blocks = append(i.Blocks, struct {
+			CompressedOffset   int64
+			UncompressedOffset int64
+		}{CompressedOffset: c, UncompressedOffset: u})
 	}
-
-	// Skip size...
-	b = b[4:]
-
-	// Check trailer...
-	if !bytes.Equal(b[:len(IndexTrailer)], []byte(IndexTrailer)) {
-		return nil, errors.New("invalid index trailer")
+	if err := s.Err(); err != nil {
+		return nil, err
 	}
-	return &i, nil
+	return i, nil
 }
@@ -0,0 +1,145 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reference
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// ErrCorrupt is returned by LZ4Converter.ConvertBlock when src ends in the
+// middle of a token, or a match's offset is 0 (never valid in LZ4).
+var ErrCorrupt = errors.New("reference: corrupt input")
+
+// ErrDstTooSmall is returned by LZ4Converter.ConvertBlock when the
+// converted block would not fit within dst's existing capacity. The
+// converter never reallocates dst itself, so callers that want a hard
+// memory bound can pre-size it and rely on this error instead.
+var ErrDstTooSmall = errors.New("reference: destination buffer too small")
+
+// LZ4Converter transcodes a raw LZ4 block into an equivalent MinLZ block,
+// re-emitting its literal runs and matches with the same emit helpers
+// encodeBlockFrom uses, without a decompress/recompress round trip. The
+// zero value is ready to use.
+type LZ4Converter struct{}
+
+// ConvertBlock converts the raw LZ4 block in src, appending the result to
+// dst. dst must already hold the MinLZ block marker byte and the
+// varint-encoded decompressed length, the same preamble EncodeBlock
+// writes, since ConvertBlock only produces the tag stream that follows it.
+// dst's capacity is never exceeded: if the converted block would grow past
+// cap(dst), ConvertBlock returns ErrDstTooSmall. It returns the appended
+// slice and the number of bytes the block decompresses to.
+func (l LZ4Converter) ConvertBlock(dst, src []byte) (out []byte, uncompressedSize int, err error) {
+	repeat := 0
+	for len(src) > 0 {
+		capBefore := cap(dst)
+
+		token := src[0]
+		src = src[1:]
+
+		litLen := int(token >> 4)
+		if litLen == 15 {
+			for {
+				if len(src) == 0 {
+					return nil, 0, ErrCorrupt
+				}
+				b := src[0]
+				src = src[1:]
+				litLen += int(b)
+				if b != 0xff {
+					break
+				}
+			}
+		}
+		if litLen > len(src) {
+			return nil, 0, ErrCorrupt
+		}
+		literals := src[:litLen]
+		src = src[litLen:]
+		uncompressedSize += litLen
+
+		// The final sequence of an LZ4 block is literals only, with no
+		// following match.
+		if len(src) == 0 {
+			if len(literals) > 0 {
+				dst = emitLiterals(dst, literals)
+			}
+			if cap(dst) != capBefore {
+				return nil, 0, ErrDstTooSmall
+			}
+			break
+		}
+
+		if len(src) < 2 {
+			return nil, 0, ErrCorrupt
+		}
+		offset := int(binary.LittleEndian.Uint16(src))
+		src = src[2:]
+		if offset == 0 {
+			return nil, 0, ErrCorrupt
+		}
+
+		matchLen := int(token & 0xf)
+		if matchLen == 15 {
+			for {
+				if len(src) == 0 {
+					return nil, 0, ErrCorrupt
+				}
+				b := src[0]
+				src = src[1:]
+				matchLen += int(b)
+				if b != 0xff {
+					break
+				}
+			}
+		}
+		matchLen += 4
+		uncompressedSize += matchLen
+
+		// Fuse short literal runs into the copy tag itself, the same way
+		// encodeBlockFrom does, rather than emitting them separately. The
+		// fused copy2 tag encodes the literal count as count-1 and can't
+		// represent zero literals, so a sequence with no literals always
+		// takes the default branch below instead.
+		canFuse := len(literals) > 0 && (len(literals) <= 3 || (offset <= 65535+64 && len(literals) <= 4)) && offset >= 64
+		switch {
+		case canFuse && offset <= 65535+64:
+			dst = emitCopyLits2(dst, literals, offset, matchLen)
+		case canFuse:
+			dst = emitCopy3(dst, offset, matchLen, literals)
+		default:
+			if len(literals) > 0 {
+				dst = emitLiterals(dst, literals)
+			}
+			switch {
+			case offset == repeat:
+				dst = emitRepeat(dst, matchLen)
+			case offset <= 1024:
+				dst = emitCopy1(dst, offset, matchLen)
+			case offset <= 65535+64:
+				dst = emitCopy2(dst, offset, matchLen)
+			default:
+				dst = emitCopy3(dst, offset, matchLen, nil)
+			}
+		}
+		repeat = offset
+
+		if cap(dst) != capBefore {
+			return nil, 0, ErrDstTooSmall
+		}
+	}
+	return dst, uncompressedSize, nil
+}
@@ -0,0 +1,224 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reference
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// IndexScanner parses an index one block at a time instead of requiring the
+// whole thing up front like LoadIndex does. The format stores every
+// uncompressed-offset delta before any compressed-offset delta, so the
+// uncompressed side still has to be read in full up front (buffered here as
+// a plain []int64, half the size of Index.Blocks' struct-pair slice); the
+// compressed side, including the running cPredict state, is decoded lazily
+// one varint per Next call. That makes
+// LookupByUncompressed able to stop reading as soon as it has its answer,
+// instead of paying for the remainder of a possibly huge index.
+type IndexScanner struct {
+	br *bufio.Reader
+
+	TotalUncompressed int64
+	TotalCompressed   int64
+	estBlockUncomp    int64
+	entries           int
+
+	uOffsets []int64
+
+	idx      int
+	prevC    int64
+	cPredict int64
+
+	curC, curU int64
+	err        error
+	done       bool
+}
+
+// NewIndexScanner parses the fixed index header from r -- the same payload
+// LoadIndexAfterHeader accepts, i.e. starting at IndexHeader, without the
+// enclosing chunk tag and length -- then reads every uncompressed-offset
+// delta, leaving r positioned at the first compressed-offset delta.
+func NewIndexScanner(r io.Reader) (*IndexScanner, error) {
+	br := bufio.NewReader(r)
+
+	hdr := make([]byte, len(IndexHeader))
+	if _, err := io.ReadFull(br, hdr); err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if !bytes.Equal(hdr, []byte(IndexHeader)) {
+		return nil, errors.New("invalid index header")
+	}
+
+	total, err := binary.ReadVarint(br)
+	if err != nil || total < 0 {
+		return nil, errors.New("unable to read uncompressed size")
+	}
+	totalComp, err := binary.ReadVarint(br)
+	if err != nil {
+		return nil, errors.New("unable to read compressed size")
+	}
+	estBlockUncomp, err := binary.ReadVarint(br)
+	if err != nil || estBlockUncomp < 0 {
+		return nil, fmt.Errorf("invalid estimated uncompressed size: %v", err)
+	}
+	entriesV, err := binary.ReadVarint(br)
+	if err != nil || entriesV < 0 || entriesV > MaxIndexEntries {
+		return nil, fmt.Errorf("invalid entry count: %d", entriesV)
+	}
+	entries := int(entriesV)
+
+	hasUncompressed, err := br.ReadByte()
+	if err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if hasUncompressed&1 != hasUncompressed {
+		return nil, errors.New("invalid has uncompressed value")
+	}
+
+	uOffsets := make([]int64, entries)
+	for idx := range uOffsets {
+		var uOff int64
+		if hasUncompressed != 0 {
+			v, err := binary.ReadVarint(br)
+			if err != nil {
+				return nil, errors.New("unable to load uncompressed delta")
+			}
+			uOff = v
+		}
+		if idx > 0 {
+			prev := uOffsets[idx-1]
+			uOff += prev + estBlockUncomp
+			if uOff <= prev {
+				return nil, fmt.Errorf("new uncompressed offset %d less than previous %d", uOff, prev)
+			}
+		}
+		if uOff < 0 {
+			return nil, errors.New("negative uncompressed offset")
+		}
+		uOffsets[idx] = uOff
+	}
+
+	return &IndexScanner{
+		br:                br,
+		TotalUncompressed: total,
+		TotalCompressed:   totalComp,
+		estBlockUncomp:    estBlockUncomp,
+		entries:           entries,
+		uOffsets:          uOffsets,
+		cPredict:          estBlockUncomp / 2,
+	}, nil
+}
+
+// Next advances to the next block, reading one compressed-offset delta from
+// the underlying reader. It returns false once every entry has been read
+// (after which Err reports any error validating the trailing footer) or
+// once an error occurs (check Err to tell the two apart).
+func (s *IndexScanner) Next() bool {
+	if s.err != nil || s.done {
+		return false
+	}
+	if s.idx >= s.entries {
+		s.done = true
+		s.err = s.checkTrailer()
+		return false
+	}
+
+	v, err := binary.ReadVarint(s.br)
+	if err != nil {
+		s.err = errors.New("unable to load delta")
+		return false
+	}
+	cOff := v
+	if s.idx > 0 {
+		cPredictNew := s.cPredict + cOff/2
+		cOff += s.prevC + s.cPredict
+		if cOff <= s.prevC {
+			s.err = fmt.Errorf("new compressed offset %d less than previous %d", cOff, s.prevC)
+			return false
+		}
+		s.cPredict = cPredictNew
+	}
+	if cOff < 0 {
+		s.err = errors.New("negative compressed offset")
+		return false
+	}
+
+	s.curC, s.curU = cOff, s.uOffsets[s.idx]
+	s.prevC = cOff
+	s.idx++
+	return true
+}
+
+// Block returns the block most recently yielded by Next.
+func (s *IndexScanner) Block() (compressedOffset, uncompressedOffset int64) {
+	return s.curC, s.curU
+}
+
+// Err returns the first error encountered, including a footer mismatch
+// discovered once Next has read every entry.
+func (s *IndexScanner) Err() error {
+	return s.err
+}
+
+// checkTrailer reads and validates the size-prefixed IndexTrailer magic that
+// follows the last entry.
+func (s *IndexScanner) checkTrailer() error {
+	var size [4]byte
+	if _, err := io.ReadFull(s.br, size[:]); err != nil {
+		return io.ErrUnexpectedEOF
+	}
+	trailer := make([]byte, len(IndexTrailer))
+	if _, err := io.ReadFull(s.br, trailer); err != nil {
+		return io.ErrUnexpectedEOF
+	}
+	if !bytes.Equal(trailer, []byte(IndexTrailer)) {
+		return errors.New("invalid index trailer")
+	}
+	return nil
+}
+
+// LookupByUncompressed consumes the scanner until it finds the block
+// covering uncompressed position off -- the last block whose
+// UncompressedOffset is <= off -- then returns it, without reading (or
+// buffering) any block past that point. It returns zero values, with no
+// error, if off is before the first indexed block. The scanner must not be
+// reused afterwards: it has read one entry past the one returned, to know
+// where to stop.
+func (s *IndexScanner) LookupByUncompressed(off int64) (compOff, uncompOff int64, err error) {
+	if off < 0 {
+		return 0, 0, fmt.Errorf("reference: negative offset %d", off)
+	}
+	var found bool
+	for s.Next() {
+		c, u := s.Block()
+		if u > off {
+			break
+		}
+		compOff, uncompOff = c, u
+		found = true
+	}
+	if err := s.Err(); err != nil {
+		return 0, 0, err
+	}
+	if !found {
+		return 0, 0, nil
+	}
+	return compOff, uncompOff, nil
+}
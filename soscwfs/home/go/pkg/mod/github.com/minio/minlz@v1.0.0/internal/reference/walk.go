@@ -0,0 +1,356 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reference
+
+import (
+	"errors"
+	"fmt"
+)
+
+// OpKind identifies the kind of operation an Op describes.
+type OpKind byte
+
+const (
+	// OpLiteral is a literal run, copied verbatim from the block's source
+	// bytes. SrcOffset and LitLen are valid; CopyOffset and CopyLen are
+	// zero.
+	OpLiteral OpKind = iota
+	// OpCopy is a back-reference copy with no embedded literal. CopyOffset
+	// and CopyLen are valid; SrcOffset and LitLen are zero.
+	OpCopy
+	// OpFusedCopy is a fused copy2/copy3 tag: a short literal run
+	// immediately followed by a copy in a single token. All four fields
+	// are valid. A copy3 tag that carries no literals is also reported as
+	// OpFusedCopy, with LitLen and SrcOffset zero.
+	OpFusedCopy
+)
+
+func (k OpKind) String() string {
+	switch k {
+	case OpLiteral:
+		return "literal"
+	case OpCopy:
+		return "copy"
+	case OpFusedCopy:
+		return "fused-copy"
+	default:
+		return fmt.Sprintf("OpKind(%d)", byte(k))
+	}
+}
+
+// Op describes a single decoded operation within a block, exactly as
+// DecodeBlock's tag switch computes it, without materializing any
+// decompressed output.
+type Op struct {
+	Kind OpKind
+
+	// SrcOffset and LitLen describe the literal bytes of an OpLiteral or
+	// OpFusedCopy op: src[SrcOffset : SrcOffset+LitLen] in the src passed
+	// to WalkBlock.
+	SrcOffset int
+	LitLen    int
+
+	// CopyOffset and CopyLen describe the back-reference of an OpCopy or
+	// OpFusedCopy op: CopyLen decoded bytes are copied from CopyOffset
+	// bytes behind the current decoded position.
+	CopyOffset uint32
+	CopyLen    int
+}
+
+// WalkBlock decodes the tag stream of a single self-contained MinLZ block
+// the same way DecodeBlock does, but instead of producing decompressed
+// output, it reports each operation to visit as it's decoded. This is
+// useful for tools that want to inspect a block's structure - a tag-usage
+// histogram for tuning encoder heuristics, a `minlz-dump`-style CLI, a test
+// asserting an encoder emits an expected tag mix, or a differential fuzzer
+// comparing the reference and optimized decoders' tag streams - without
+// paying for a full decode.
+//
+// If visit returns an error, WalkBlock stops and returns it unwrapped.
+func WalkBlock(src []byte, visit func(op Op) error) error {
+	if len(src) == 0 {
+		return errors.New("src length is zero")
+	}
+	if src[0] != 0 {
+		return errors.New("first byte is not 0")
+	}
+	orig := src
+	if len(src) == 1 {
+		return nil
+	}
+	src = src[1:]
+
+	var wantSize int
+	for i := uint(0); ; i += 7 {
+		if i == 7*10 {
+			return fmt.Errorf("invalid destination size")
+		}
+		if len(src) == 0 {
+			return errors.New("unable to read length")
+		}
+		v := src[0]
+		wantSize |= int(v&0x7f) << i
+		if wantSize > maxBlockSize {
+			return fmt.Errorf("invalid destination size")
+		}
+		src = src[1:]
+		if v&0x80 == 0 {
+			break
+		}
+	}
+	if wantSize == 0 {
+		return nil
+	}
+	if wantSize < len(src) {
+		return fmt.Errorf("decompressed smaller than compressed size %d", wantSize)
+	}
+
+	pos := 0
+	checkSize := func(n uint32) bool {
+		return n < maxBlockSize && pos+int(n) <= wantSize
+	}
+
+	readOne := func() (v uint32, ok bool) {
+		if len(src) >= 1 {
+			v = uint32(src[0])
+			src = src[1:]
+			return v, true
+		}
+		return 0, false
+	}
+	readTwo := func() (v uint32, ok bool) {
+		if len(src) >= 2 {
+			v = uint32(src[0]) | uint32(src[1])<<8
+			src = src[2:]
+			return v, true
+		}
+		return 0, false
+	}
+	readThree := func() (v uint32, ok bool) {
+		if len(src) >= 3 {
+			v = uint32(src[0]) | uint32(src[1])<<8 | uint32(src[2])<<16
+			src = src[3:]
+			return v, true
+		}
+		return 0, false
+	}
+	skipN := func(n uint32) bool {
+		if uint32(len(src)) >= n {
+			src = src[n:]
+			return true
+		}
+		return false
+	}
+
+	var offset = uint32(1)
+
+	for len(src) > 0 {
+		v, ok := readOne()
+		if !ok {
+			break
+		}
+		tag := v & 3
+		value := v >> 2
+		var length uint32
+		switch tag {
+		case 0: // Literal/repeat tag.
+			isRepeat := value&1 != 0
+			value = value >> 1
+			switch {
+			case value < 29:
+				length = value + 1
+			case value == 29:
+				length, ok = readOne()
+				if !ok {
+					return fmt.Errorf("lit tag 29: unable to read length at pos %d", pos)
+				}
+				length += 30
+			case value == 30:
+				length, ok = readTwo()
+				if !ok {
+					return fmt.Errorf("lit tag 30: unable to read length at pos %d", pos)
+				}
+				length += 30
+			case value == 31:
+				length, ok = readThree()
+				if !ok {
+					return fmt.Errorf("lit tag 31: unable to read length at pos %d", pos)
+				}
+				length += 30
+			}
+
+			if isRepeat {
+				if !checkSize(length) {
+					return fmt.Errorf("copy length %d exceeds dst size at pos %d", length, pos)
+				}
+				if int(offset) > pos {
+					return fmt.Errorf("copy offset %d exceeds decoded size %d", offset, pos)
+				}
+				if err := visit(Op{Kind: OpCopy, CopyOffset: offset, CopyLen: int(length)}); err != nil {
+					return err
+				}
+				pos += int(length)
+				continue
+			}
+
+			if !checkSize(length) {
+				return fmt.Errorf("literal length %d exceed destination at pos %d", length, pos)
+			}
+			srcOffset := len(orig) - len(src)
+			if !skipN(length) {
+				return fmt.Errorf("literal length %d exceed source at pos %d", length, pos)
+			}
+			if err := visit(Op{Kind: OpLiteral, SrcOffset: srcOffset, LitLen: int(length)}); err != nil {
+				return err
+			}
+			pos += int(length)
+			continue
+
+		case 1: // Copy with 1 byte extra offset.
+			length = value & 15
+			offset, ok = readOne()
+			if !ok {
+				return fmt.Errorf("copy 1: unable to read offset at pos %d", pos)
+			}
+			offset = offset<<2 | (value >> 4)
+			if length == 15 {
+				length, ok = readOne()
+				if !ok {
+					return fmt.Errorf("copy 1: unable to read length at pos %d", pos)
+				}
+				length += 18
+			} else {
+				length += 4
+			}
+			offset++
+
+		case 2: // Copy with 2 byte offset.
+			offset, ok = readTwo()
+			if !ok {
+				return fmt.Errorf("copy 2: unable to read offset at pos %d", pos)
+			}
+			switch {
+			case value <= 60:
+				length = value + 4
+			case value == 61:
+				length, ok = readOne()
+				if !ok {
+					return fmt.Errorf("copy 2.61: unable to read length at pos %d", pos)
+				}
+				length += 64
+			case value == 62:
+				length, ok = readTwo()
+				if !ok {
+					return fmt.Errorf("copy 2.62: unable to read length at pos %d", pos)
+				}
+				length += 64
+			case value == 63:
+				length, ok = readThree()
+				if !ok {
+					return fmt.Errorf("copy 2.63: unable to read length at pos %d", pos)
+				}
+				length += 64
+			}
+			offset += 64
+
+		case 3: // Fused copy2 or copy3.
+			isCopy3 := value&1 == 1
+			litLen := value >> 1 & 3
+
+			if !isCopy3 {
+				offset, ok = readTwo()
+				if !ok {
+					return fmt.Errorf("copy 2, fused: unable to read offset at pos %d", pos)
+				}
+				length = (value >> 3) + 4
+				litLen++
+				offset += 64
+			} else {
+				v2, ok := readThree()
+				if !ok {
+					return fmt.Errorf("copy 3: unable to read value at pos %d", pos)
+				}
+				value = value | v2<<6
+				offset = (value >> 9) + 65536
+				value = (value >> 3) & 63
+				switch {
+				case value < 61:
+					length = value + 4
+				case value == 61:
+					length, ok = readOne()
+					if !ok {
+						return fmt.Errorf("copy 3.29: unable to read length at pos %d", pos)
+					}
+					length += 64
+				case value == 62:
+					length, ok = readTwo()
+					if !ok {
+						return fmt.Errorf("copy 3.30: unable to read length at pos %d", pos)
+					}
+					length += 64
+				case value == 63:
+					length, ok = readThree()
+					if !ok {
+						return fmt.Errorf("copy 3.31: unable to read length at pos %d", pos)
+					}
+					length += 64
+				}
+			}
+
+			var litSrcOffset int
+			if litLen > 0 {
+				if !checkSize(litLen) {
+					return fmt.Errorf("copy 3: extra literal output size exceeded at pos %d", pos)
+				}
+				litSrcOffset = len(orig) - len(src)
+				if !skipN(litLen) {
+					return fmt.Errorf("copy 3: unable to read extra literals at pos %d", pos)
+				}
+			}
+			if !checkSize(length) {
+				return fmt.Errorf("copy length %d exceeds dst size at pos %d", length, pos)
+			}
+			if int(offset) > pos+int(litLen) {
+				return fmt.Errorf("copy offset %d exceeds decoded size %d", offset, pos+int(litLen))
+			}
+			if err := visit(Op{
+				Kind:       OpFusedCopy,
+				SrcOffset:  litSrcOffset,
+				LitLen:     int(litLen),
+				CopyOffset: offset,
+				CopyLen:    int(length),
+			}); err != nil {
+				return err
+			}
+			pos += int(litLen) + int(length)
+			continue
+		}
+
+		if !checkSize(length) {
+			return fmt.Errorf("copy length %d exceeds dst size at pos %d", length, pos)
+		}
+		if int(offset) > pos {
+			return fmt.Errorf("copy offset %d exceeds decoded size %d", offset, pos)
+		}
+		if err := visit(Op{Kind: OpCopy, CopyOffset: offset, CopyLen: int(length)}); err != nil {
+			return err
+		}
+		pos += int(length)
+	}
+	if pos != wantSize {
+		return fmt.Errorf("mismatching output size, got %d, want %d", pos, wantSize)
+	}
+	return nil
+}
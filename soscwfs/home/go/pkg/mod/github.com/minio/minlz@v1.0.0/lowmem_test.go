@@ -0,0 +1,65 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWriterLowMem(t *testing.T) {
+	data := []byte(strings.Repeat("the quick brown fox jumps over the lazy dog ", 200))
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, WriterLowMem(true))
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	got, err := io.ReadAll(NewReader(bytes.NewReader(buf.Bytes())))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatal("roundtrip mismatch")
+	}
+}
+
+func TestWriterLowMemWithDict(t *testing.T) {
+	dict := []byte(strings.Repeat("shared dictionary content ", 50))
+	data := []byte(strings.Repeat("shared dictionary content plus some new text ", 100))
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, WriterLowMem(true), WithDictionary(dict))
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	got, err := io.ReadAll(NewReader(bytes.NewReader(buf.Bytes()), ReaderDict(NewDict(dict))))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatal("roundtrip mismatch")
+	}
+}
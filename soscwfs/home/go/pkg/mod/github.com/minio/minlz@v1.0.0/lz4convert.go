@@ -0,0 +1,200 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// errIncompressible is returned by the LZ4/Snappy converters when dst does
+// not have enough room for the converted output.
+var errIncompressible = errors.New("minlz: not enough space in dst")
+
+// lZ4Converter transcodes raw LZ4 blocks (and, via ConvertBlockSnappy, raw
+// Snappy blocks) into MinLZ blocks without a decompress/recompress round
+// trip. The zero value is ready to use.
+type lZ4Converter struct{}
+
+// ConvertBlock converts a single raw LZ4 block in src into a MinLZ block,
+// appending it to dst. dst must already contain the marker byte and the
+// varint-encoded decompressed length, as produced by the caller (see
+// lz4convert_test.go). It returns the appended dst and the number of
+// decompressed bytes represented by the block.
+func (l lZ4Converter) ConvertBlock(dst, src []byte) ([]byte, int, error) {
+	return convertLZ4Block(dst, src, false)
+}
+
+// ConvertBlockSnappy converts a raw Snappy block the same way ConvertBlock
+// converts an LZ4 block.
+func (l lZ4Converter) ConvertBlockSnappy(dst, src []byte) ([]byte, int, error) {
+	return convertSnappyBlock(dst, src)
+}
+
+// convertLZ4Block walks the LZ4 token stream in src and re-emits every
+// literal run and match using the MinLZ emit primitives, preserving offsets
+// and lengths verbatim. lz4s selects the QAT LZ4s length-encoding variant
+// (see convertLZ4sBlock in lz4sconvert.go).
+func convertLZ4Block(dst, src []byte, lz4s bool) ([]byte, int, error) {
+	if lz4s {
+		return convertLZ4sBlock(dst, src)
+	}
+	decoded := 0
+	repeat := 0
+	for len(src) > 0 {
+		token := src[0]
+		src = src[1:]
+
+		litLen := int(token >> 4)
+		if litLen == 15 {
+			for {
+				if len(src) == 0 {
+					return nil, 0, ErrCorrupt
+				}
+				b := src[0]
+				src = src[1:]
+				litLen += int(b)
+				if b != 0xff {
+					break
+				}
+			}
+		}
+		if litLen > 0 {
+			if litLen > len(src) {
+				return nil, 0, ErrCorrupt
+			}
+			dst = emitLiteral(dst, src[:litLen])
+			src = src[litLen:]
+			decoded += litLen
+		}
+
+		// The final sequence of an LZ4 block has no match part.
+		if len(src) == 0 {
+			break
+		}
+		if len(src) < 2 {
+			return nil, 0, ErrCorrupt
+		}
+		offset := int(binary.LittleEndian.Uint16(src))
+		src = src[2:]
+		if offset == 0 {
+			return nil, 0, ErrCorrupt
+		}
+
+		matchLen := int(token & 0xf)
+		if matchLen == 15 {
+			for {
+				if len(src) == 0 {
+					return nil, 0, ErrCorrupt
+				}
+				b := src[0]
+				src = src[1:]
+				matchLen += int(b)
+				if b != 0xff {
+					break
+				}
+			}
+		}
+		matchLen += 4
+
+		if offset == repeat {
+			dst = emitRepeat(dst, matchLen)
+		} else {
+			dst = emitCopy(dst, offset, matchLen)
+		}
+		repeat = offset
+		decoded += matchLen
+	}
+	return dst, decoded, nil
+}
+
+// convertSnappyBlock converts a raw (legacy) Snappy block, whose tag set is
+// a strict subset of MinLZ's, into an equivalent MinLZ block.
+func convertSnappyBlock(dst, src []byte) ([]byte, int, error) {
+	decoded := 0
+	for len(src) > 0 {
+		tag := src[0] & 3
+		value := int(src[0] >> 2)
+		src = src[1:]
+
+		switch tag {
+		case 0: // literal
+			length := value + 1
+			switch {
+			case value == 60:
+				if len(src) < 1 {
+					return nil, 0, ErrCorrupt
+				}
+				length = int(src[0]) + 1
+				src = src[1:]
+			case value == 61:
+				if len(src) < 2 {
+					return nil, 0, ErrCorrupt
+				}
+				length = int(binary.LittleEndian.Uint16(src)) + 1
+				src = src[2:]
+			case value == 62:
+				if len(src) < 3 {
+					return nil, 0, ErrCorrupt
+				}
+				length = int(src[0]) | int(src[1])<<8 | int(src[2])<<16 + 1
+				src = src[3:]
+			case value == 63:
+				if len(src) < 4 {
+					return nil, 0, ErrCorrupt
+				}
+				length = int(binary.LittleEndian.Uint32(src)) + 1
+				src = src[4:]
+			}
+			if length > len(src) {
+				return nil, 0, ErrCorrupt
+			}
+			dst = emitLiteral(dst, src[:length])
+			src = src[length:]
+			decoded += length
+
+		case 1: // copy with 1-byte offset
+			if len(src) < 1 {
+				return nil, 0, ErrCorrupt
+			}
+			length := value&7 + 4
+			offset := (value>>3)<<8 | int(src[0])
+			src = src[1:]
+			dst = emitCopy(dst, offset, length)
+			decoded += length
+
+		case 2: // copy with 2-byte offset
+			if len(src) < 2 {
+				return nil, 0, ErrCorrupt
+			}
+			length := value + 1
+			offset := int(binary.LittleEndian.Uint16(src))
+			src = src[2:]
+			dst = emitCopy(dst, offset, length)
+			decoded += length
+
+		case 3: // copy with 4-byte offset
+			if len(src) < 4 {
+				return nil, 0, ErrCorrupt
+			}
+			length := value + 1
+			offset := int(binary.LittleEndian.Uint32(src))
+			src = src[4:]
+			dst = emitCopy(dst, offset, length)
+			decoded += length
+		}
+	}
+	return dst, decoded, nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+import "encoding/binary"
+
+// LZ4sConverter transcodes raw LZ4s blocks, the variant emitted by Intel
+// QAT's hardware compression engine, into MinLZ blocks without a
+// decompress/recompress round trip, mirroring lZ4Converter for plain LZ4.
+// The zero value is ready to use.
+//
+// LZ4s differs from standard LZ4 in its token's match-length nibble: since
+// QAT only ever emits matches of length 4 or more, codes 1-15 map directly
+// to match lengths 4-18 (with 18 extended further by trailing length bytes,
+// the same way standard LZ4's code 15 extends), and the otherwise-unused
+// code 0 is repurposed to mean "no match here" - the literal bytes in this
+// token continue into the next token's literal run instead of being
+// followed by an offset.
+type LZ4sConverter struct{}
+
+// ConvertBlock converts a single raw LZ4s block in src into a MinLZ block,
+// appending it to dst, the same way lZ4Converter.ConvertBlock does for
+// standard LZ4.
+func (l LZ4sConverter) ConvertBlock(dst, src []byte) ([]byte, int, error) {
+	return convertLZ4sBlock(dst, src)
+}
+
+// convertLZ4sBlock walks an LZ4s token stream, re-emitting every literal run
+// and match using the MinLZ emit primitives. Zero-length "copy" codes carry
+// no offset and are merged into the following token's literal run rather
+// than treated as a match.
+func convertLZ4sBlock(dst, src []byte) ([]byte, int, error) {
+	decoded := 0
+	repeat := 0
+	var pendingLit []byte
+
+	for len(src) > 0 {
+		token := src[0]
+		src = src[1:]
+
+		litLen := int(token >> 4)
+		if litLen == 15 {
+			for {
+				if len(src) == 0 {
+					return nil, 0, ErrCorrupt
+				}
+				b := src[0]
+				src = src[1:]
+				litLen += int(b)
+				if b != 0xff {
+					break
+				}
+			}
+		}
+		if litLen > 0 {
+			if litLen > len(src) {
+				return nil, 0, ErrCorrupt
+			}
+			pendingLit = append(pendingLit, src[:litLen]...)
+			src = src[litLen:]
+		}
+
+		matchLen := int(token & 0xf)
+
+		// The final sequence of a block has no match part, regardless of
+		// what the match-length nibble says.
+		if len(src) == 0 {
+			break
+		}
+
+		if matchLen == 0 {
+			// LZ4s reserves this code for "no match here"; its literal
+			// bytes (already buffered above) continue into the next
+			// token's literal run.
+			continue
+		}
+
+		if len(pendingLit) > 0 {
+			dst = emitLiteral(dst, pendingLit)
+			decoded += len(pendingLit)
+			pendingLit = pendingLit[:0]
+		}
+
+		if len(src) < 2 {
+			return nil, 0, ErrCorrupt
+		}
+		offset := int(binary.LittleEndian.Uint16(src))
+		src = src[2:]
+		if offset == 0 {
+			return nil, 0, ErrCorrupt
+		}
+
+		if matchLen == 15 {
+			for {
+				if len(src) == 0 {
+					return nil, 0, ErrCorrupt
+				}
+				b := src[0]
+				src = src[1:]
+				matchLen += int(b)
+				if b != 0xff {
+					break
+				}
+			}
+		}
+		matchLen += 3 // codes 1-15 mean lengths 4-18, i.e. code+3.
+
+		if offset == repeat {
+			dst = emitRepeat(dst, matchLen)
+		} else {
+			dst = emitCopy(dst, offset, matchLen)
+		}
+		repeat = offset
+		decoded += matchLen
+	}
+
+	if len(pendingLit) > 0 {
+		dst = emitLiteral(dst, pendingLit)
+		decoded += len(pendingLit)
+	}
+	return dst, decoded, nil
+}
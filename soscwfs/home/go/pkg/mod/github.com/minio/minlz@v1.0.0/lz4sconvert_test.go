@@ -0,0 +1,94 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"github.com/minio/minlz/internal/fuzz"
+)
+
+// TestLZ4sConverter_ConvertBlockSingle exercises the LZ4s-specific token encoding:
+// a zero-code "copy" that merges literal runs across it, a repeated offset
+// collapsing to emitRepeat, and a final literal-only sequence.
+func TestLZ4sConverter_ConvertBlockSingle(t *testing.T) {
+	// Sequences:
+	//  1. literal "AAAA", match offset=4 len=4   -> "AAAA"
+	//  2. no literal, match offset=4 len=4 (repeat) -> "AAAA"
+	//  3. literal "BB", code 0 (no match, merge into next literal run)
+	//  4. literal "CC", final sequence (no match part)
+	// Decoded: "AAAA" + "AAAA" + "AAAA" + "BBCC"
+	lz4sData := []byte{
+		0x41, 0x41, 0x41, 0x41, 0x41, 0x04, 0x00,
+		0x01, 0x04, 0x00,
+		0x20, 0x42, 0x42,
+		0x20, 0x43, 0x43,
+	}
+	want := []byte("AAAAAAAAAAAABBCC")
+
+	dst := make([]byte, 1+binary.MaxVarintLen32, MaxEncodedLen(len(want))+1+binary.MaxVarintLen32)
+	dst[0] = 0
+	dst = dst[:1+binary.PutUvarint(dst[1:], uint64(len(want)))]
+
+	conv := LZ4sConverter{}
+	out, n, err := conv.ConvertBlock(dst, lz4sData)
+	if err != nil {
+		t.Fatalf("ConvertBlock: %v", err)
+	}
+	if n != len(want) {
+		t.Fatalf("decoded length = %d, want %d", n, len(want))
+	}
+
+	got, err := decodeGo(nil, out)
+	if err != nil {
+		t.Fatalf("decodeGo: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func FuzzLZ4sBlock(f *testing.F) {
+	fuzz.AddFromZip(f, "testdata/fuzz/FuzzLZ4sBlock.zip", fuzz.TypeRaw, false)
+	const maxSize = 1 << 20
+
+	conv := LZ4sConverter{}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if len(data) > maxSize || len(data) == 0 {
+			return
+		}
+		body, n, err := conv.ConvertBlock(nil, data)
+		if err != nil {
+			return
+		}
+		if n < 0 {
+			panic(fmt.Sprintf("negative decoded length %d", n))
+		}
+		// A successful conversion must always decode cleanly back through
+		// the MinLZ decoder, regardless of whether it matches any
+		// particular LZ4s encoder's output.
+		header := make([]byte, 1, 1+binary.MaxVarintLen32)
+		header = append(header, make([]byte, binary.MaxVarintLen32)...)
+		header = header[:1+binary.PutUvarint(header[1:], uint64(n))]
+		full := append(header, body...)
+		if _, err := decodeGo(nil, full); err != nil {
+			panic(fmt.Sprintf("decode of converted block failed: %v", err))
+		}
+	})
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package minlz implements the MinLZ compression format: a Snappy-derived
+// block and stream format with a richer tag set for better ratio at
+// comparable speed.
+package minlz
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// MaxBlockSize is the largest block that can be encoded or decoded in a
+// single call to Encode/Decode.
+const MaxBlockSize = 8 << 20
+
+// Level selects a compression/speed tradeoff for Encode and the Writer.
+type Level int
+
+const (
+	// LevelFastest uses a single hash table and greedy matching.
+	LevelFastest Level = iota
+	// LevelBalanced is accepted, but uses the same single-table greedy
+	// matcher as LevelFastest and produces identical output.
+	LevelBalanced
+	// LevelSmallest is accepted, but uses the same single-table greedy
+	// matcher as LevelFastest and produces identical output.
+	LevelSmallest
+)
+
+var (
+	// ErrCorrupt reports that the input is invalid.
+	ErrCorrupt = errors.New("minlz: corrupt input")
+	// ErrTooLarge reports that the decoded output would be larger than MaxBlockSize.
+	ErrTooLarge = errors.New("minlz: decoded block too large")
+	// ErrDstTooSmall reports that the destination buffer was too small to hold the encoded output.
+	ErrDstTooSmall = errors.New("minlz: destination buffer too small")
+	// ErrInvalidLevel reports that an undefined Level was passed to Encode.
+	ErrInvalidLevel = errors.New("minlz: invalid level")
+)
+
+// MaxEncodedLen returns the maximum length of an encoded block, given its
+// uncompressed length.
+//
+// It will return a negative value if srcLen is too large to encode.
+func MaxEncodedLen(srcLen int) int {
+	if srcLen < 0 || srcLen > MaxBlockSize {
+		return -1
+	}
+	if srcLen == 0 {
+		return 1
+	}
+	// Marker byte, varint length (at most 5 bytes for our range) and a
+	// couple of bytes of worst case tag overhead.
+	return srcLen + binary.MaxVarintLen32 + 2
+}
+
+// isMinLZ parses the leading marker and length of a MinLZ block.
+//
+// lits reports whether the remaining bytes in block are raw, uncompressed
+// literals (in which case dLen is simply len(block)). Otherwise block holds
+// the tag-encoded payload and dLen is the expected decoded length.
+func isMinLZ(src []byte) (isMLZ bool, lits bool, block []byte, dLen int, err error) {
+	if len(src) == 0 {
+		return false, false, nil, 0, ErrCorrupt
+	}
+	if src[0] != 0 {
+		return false, false, nil, 0, nil
+	}
+	src = src[1:]
+	if len(src) == 0 {
+		// A lone marker byte represents a zero-length block.
+		return true, true, nil, 0, nil
+	}
+
+	wantSize, n := binary.Uvarint(src)
+	if n <= 0 || wantSize > MaxBlockSize {
+		return true, false, nil, 0, ErrCorrupt
+	}
+	src = src[n:]
+
+	if wantSize == 0 {
+		// Sentinel for "stored uncompressed"; remainder is the literal payload.
+		return true, true, src, len(src), nil
+	}
+	return true, false, src, int(wantSize), nil
+}
+
+// DecodedLen returns the length of the decoded block.
+func DecodedLen(src []byte) (int, error) {
+	isMLZ, _, _, dLen, err := isMinLZ(src)
+	if err != nil {
+		return 0, err
+	}
+	if !isMLZ {
+		return 0, ErrCorrupt
+	}
+	return dLen, nil
+}
+
+// Decode decodes a block encoded by Encode and appends it to dst, which may
+// be nil. It returns the decoded block.
+func Decode(dst, src []byte) ([]byte, error) {
+	isMLZ, lits, block, dLen, err := isMinLZ(src)
+	if err != nil {
+		return nil, err
+	}
+	if !isMLZ {
+		return nil, ErrCorrupt
+	}
+	if dLen > MaxBlockSize {
+		return nil, ErrTooLarge
+	}
+	if lits {
+		return append(dst[:0], block...), nil
+	}
+
+	var out []byte
+	if dLen <= cap(dst) {
+		out = dst[:dLen]
+	} else {
+		out = make([]byte, dLen)
+	}
+	if minLZDecodeGo(out, block) != 0 {
+		return nil, ErrCorrupt
+	}
+	return out, nil
+}
@@ -71,7 +71,7 @@ func TestMaxEncodedLen(t *testing.T) {
 	}
 }
 
-func encodeGo(dst, src []byte, level int) []byte {
+func encodeGo(dst, src []byte, level Level) []byte {
 	if n := MaxEncodedLen(len(src)); n < 0 {
 		panic(ErrTooLarge)
 	} else if len(dst) < n {
@@ -1375,7 +1375,7 @@ func testWriterRoundtrip(t *testing.T, src []byte, opts ...WriterOption) {
 	}
 }
 
-func testBlockRoundtrip(t *testing.T, src []byte, level int) {
+func testBlockRoundtrip(t *testing.T, src []byte, level Level) {
 	dst, _ := Encode(nil, src, level)
 	t.Logf("encoded to %d -> %d bytes", len(src), len(dst))
 	decoded, err := Decode(nil, dst)
@@ -0,0 +1,103 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package minlzzip registers minlz as a ZIP compression method, so it can be
+// used as a drop-in alternative to deflate/zstd entries in
+// github.com/klauspost/compress/zip archives, or in the standard library's
+// archive/zip.
+package minlzzip
+
+import (
+	stdzip "archive/zip"
+	"io"
+
+	"github.com/klauspost/compress/zip"
+	"github.com/minio/minlz"
+)
+
+// Method is the ZIP compression method ID used for minlz entries. It is not
+// assigned by the PKWARE APPNOTE, so it only has meaning between writers and
+// readers that both call Register/RegisterStd (the same way
+// klauspost/compress/zip reserves 93 for zstd). 96 was picked simply because
+// it is free.
+const Method uint16 = 96
+
+// DefaultBlockSize is the uncompressed size of each chunk a registered
+// compressor emits, when Options.BlockSize is left at 0. ZIP entries are
+// streamed one at a time rather than seeked into, so this is chosen small
+// enough to keep memory use modest even with many open entries.
+const DefaultBlockSize = 128 << 10
+
+// Options configures the compressor Register/RegisterOptions/RegisterStd
+// install.
+type Options struct {
+	Level minlz.Level
+	// BlockSize overrides DefaultBlockSize; see minlz.WriterBlockSize.
+	BlockSize int
+	// Concurrency sets the number of blocks a compressor may have in
+	// flight at once; see minlz.WriterConcurrency. 0 uses the minlz.Writer
+	// default.
+	Concurrency int
+}
+
+// Register installs minlz as method Method on the process-wide compressor
+// and decompressor tables github.com/klauspost/compress/zip's
+// Writer/Reader use. It is RegisterOptions with Options{Level: level} and
+// BlockSize/Concurrency left at their defaults.
+func Register(level minlz.Level) {
+	RegisterOptions(Options{Level: level})
+}
+
+// RegisterOptions is Register with full control over the installed
+// compressor's block size and concurrency.
+func RegisterOptions(opts Options) {
+	zip.RegisterCompressor(Method, newCompressor(opts))
+	zip.RegisterDecompressor(Method, newDecompressor())
+}
+
+// RegisterStd is RegisterOptions for the standard library's archive/zip
+// instead of github.com/klauspost/compress/zip. The two packages keep
+// separate compressor/decompressor tables, so registering one does not
+// register the other.
+func RegisterStd(opts Options) {
+	stdzip.RegisterCompressor(Method, newCompressor(opts))
+	stdzip.RegisterDecompressor(Method, newDecompressor())
+}
+
+// newCompressor returns a zip.Compressor (the function type is identical
+// between archive/zip and klauspost/compress/zip) that streams writes
+// through a minlz.Writer configured per opts. Closing the returned
+// io.WriteCloser flushes the final block, so the enclosing zip.Writer sees
+// the entry's true compressed size and CRC-32 once it finishes the entry.
+func newCompressor(opts Options) func(io.Writer) (io.WriteCloser, error) {
+	blockSize := opts.BlockSize
+	if blockSize <= 0 {
+		blockSize = DefaultBlockSize
+	}
+	return func(w io.Writer) (io.WriteCloser, error) {
+		wopts := []minlz.WriterOption{minlz.WriterLevel(opts.Level), minlz.WriterBlockSize(blockSize)}
+		if opts.Concurrency > 0 {
+			wopts = append(wopts, minlz.WriterConcurrency(opts.Concurrency))
+		}
+		return minlz.NewWriter(w, wopts...), nil
+	}
+}
+
+// newDecompressor returns a zip.Decompressor that streams reads through a
+// minlz.Reader.
+func newDecompressor() func(io.Reader) io.ReadCloser {
+	return func(r io.Reader) io.ReadCloser {
+		return io.NopCloser(minlz.NewReader(r))
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlzzip
+
+import (
+	stdzip "archive/zip"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/klauspost/compress/zip"
+	"github.com/minio/minlz"
+)
+
+func TestRoundtrip(t *testing.T) {
+	const want = "the quick brown fox jumps over the lazy dog, repeated for good measure: " +
+		"the quick brown fox jumps over the lazy dog"
+
+	// Each level gets its own method id rather than sharing Method: the zip
+	// package's compressor/decompressor tables are process-wide and panic
+	// on a second registration of the same id, so re-registering Method for
+	// every level here would panic on the second iteration.
+	levels := []minlz.Level{minlz.LevelFastest, minlz.LevelBalanced, minlz.LevelSmallest}
+	for i, level := range levels {
+		method := Method + 1 + uint16(i)
+		zip.RegisterCompressor(method, newCompressor(Options{Level: level}))
+		zip.RegisterDecompressor(method, newDecompressor())
+
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:   "entry.txt",
+			Method: method,
+		})
+		if err != nil {
+			t.Fatalf("level %d: CreateHeader: %v", level, err)
+		}
+		if _, err := fw.Write([]byte(want)); err != nil {
+			t.Fatalf("level %d: Write: %v", level, err)
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatalf("level %d: zip.Writer.Close: %v", level, err)
+		}
+
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		if err != nil {
+			t.Fatalf("level %d: zip.NewReader: %v", level, err)
+		}
+		if len(zr.File) != 1 {
+			t.Fatalf("level %d: got %d entries, want 1", level, len(zr.File))
+		}
+		if zr.File[0].Method != method {
+			t.Fatalf("level %d: entry method = %d, want %d", level, zr.File[0].Method, method)
+		}
+		rc, err := zr.File[0].Open()
+		if err != nil {
+			t.Fatalf("level %d: Open: %v", level, err)
+		}
+		got, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("level %d: ReadAll: %v", level, err)
+		}
+		if string(got) != want {
+			t.Fatalf("level %d: got %q, want %q", level, got, want)
+		}
+	}
+}
+
+// TestRoundtripStd is TestRoundtrip against the standard library's
+// archive/zip instead of github.com/klauspost/compress/zip, exercising
+// RegisterStd's separate compressor/decompressor tables.
+func TestRoundtripStd(t *testing.T) {
+	const want = "the quick brown fox jumps over the lazy dog, repeated for good measure: " +
+		"the quick brown fox jumps over the lazy dog"
+
+	RegisterStd(Options{Level: minlz.LevelBalanced, BlockSize: 4 << 10, Concurrency: 2})
+
+	var buf bytes.Buffer
+	zw := stdzip.NewWriter(&buf)
+	fw, err := zw.CreateHeader(&stdzip.FileHeader{
+		Name:   "entry.txt",
+		Method: Method,
+	})
+	if err != nil {
+		t.Fatalf("CreateHeader: %v", err)
+	}
+	if _, err := fw.Write([]byte(want)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("zip.Writer.Close: %v", err)
+	}
+
+	zr, err := stdzip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("zip.NewReader: %v", err)
+	}
+	if len(zr.File) != 1 {
+		t.Fatalf("got %d entries, want 1", len(zr.File))
+	}
+	if zr.File[0].UncompressedSize64 != uint64(len(want)) {
+		t.Fatalf("uncompressed size = %d, want %d", zr.File[0].UncompressedSize64, len(want))
+	}
+	rc, err := zr.File[0].Open()
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	got, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if string(got) != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+// FuzzZipDecode feeds mutated zip archives through a registered minlz
+// decompressor, mirroring the shape of minlz's own FuzzStreamDecode but
+// through a zip container instead of a bare minlz stream: seeds are valid
+// archives built from Register, and the fuzzer looks only for panics or
+// hangs, not matching output (a corrupt archive is expected to error).
+func FuzzZipDecode(f *testing.F) {
+	Register(minlz.LevelFastest)
+	seed := func(payload []byte) {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: "f", Method: Method})
+		if err != nil {
+			return
+		}
+		if _, err := fw.Write(payload); err != nil {
+			return
+		}
+		if err := zw.Close(); err != nil {
+			return
+		}
+		f.Add(buf.Bytes())
+	}
+	seed(nil)
+	seed([]byte("hello world"))
+	seed(bytes.Repeat([]byte("the quick brown fox "), 1000))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return
+		}
+		for _, zf := range zr.File {
+			if zf.Method != Method {
+				continue
+			}
+			rc, err := zf.Open()
+			if err != nil {
+				continue
+			}
+			io.Copy(io.Discard, rc)
+			rc.Close()
+		}
+	})
+}
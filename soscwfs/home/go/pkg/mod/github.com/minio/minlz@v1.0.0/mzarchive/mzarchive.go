@@ -0,0 +1,239 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mzarchive is a MinLZ-native alternative to mztar/tar.mz or a
+// minlzzip .zip: a container of independently MinLZ-compressed entries
+// (files, directory trees, whatever the caller adds) followed by a central
+// directory listing each entry's name, mode, mtime, sizes and offset, the
+// way a ZIP's central directory trails its members. Every entry is its own
+// complete MinLZ stream, so Reader.Open and Reader.Extract can fetch and
+// decode one member -- in parallel with minlz.Reader.DecodeConcurrent, or
+// several members at once from separate goroutines -- without touching any
+// other entry.
+package mzarchive
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/minio/minlz"
+)
+
+// cdTrailerMagic identifies the fixed footer Writer.Close appends after the
+// last entry, the same way mztar's tocTrailerMagic footers a table of
+// contents: a 4-byte length followed by this magic, read from the end of
+// the archive, locates the central directory without decoding any entry.
+const cdTrailerMagic = "mzarcdir"
+
+// Entry records one member of an archive written by a Writer: everything
+// Reader needs to list it and fetch its content on its own, without reading
+// any other entry first.
+type Entry struct {
+	Name    string      `json:"name"`
+	Mode    os.FileMode `json:"mode"`
+	ModTime time.Time   `json:"modtime"`
+
+	UncompressedSize int64 `json:"uncompressedSize"`
+	CompressedSize   int64 `json:"compressedSize"`
+
+	// Offset is where this entry's MinLZ stream starts in the archive.
+	// The stream occupies exactly [Offset, Offset+CompressedSize).
+	Offset int64 `json:"offset"`
+}
+
+// countingWriter tracks how many bytes have reached the underlying
+// io.Writer, so Writer can record each entry's offset and compressed size
+// as it writes, mirroring mztar's own countingWriter.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// Writer builds an mzarchive container: each Add call appends one entry as
+// its own independent MinLZ stream, and Close appends the central
+// directory describing every entry added so far.
+type Writer struct {
+	cw      *countingWriter
+	opts    []minlz.WriterOption
+	entries []Entry
+}
+
+// NewWriter returns a Writer that streams entries to w, each one compressed
+// with opts -- the same options minlz.NewWriter would otherwise take.
+// WriterSeekable is forced off for every entry: an mzarchive's own central
+// directory already gives random access to entries, so a per-entry index
+// footer would only add overhead.
+func NewWriter(w io.Writer, opts ...minlz.WriterOption) *Writer {
+	return &Writer{cw: &countingWriter{w: w}, opts: opts}
+}
+
+// Add compresses the content read from r as a new entry named name, and
+// records mode and modTime alongside it in the central directory Close will
+// write. It returns the entry's uncompressed size.
+func (a *Writer) Add(name string, mode os.FileMode, modTime time.Time, r io.Reader) (int64, error) {
+	offset := a.cw.n
+	opts := append(append([]minlz.WriterOption{}, a.opts...), minlz.WriterSeekable(false))
+	mw := minlz.NewWriter(a.cw, opts...)
+	n, err := io.Copy(mw, r)
+	if err != nil {
+		return 0, err
+	}
+	if err := mw.Close(); err != nil {
+		return 0, err
+	}
+	a.entries = append(a.entries, Entry{
+		Name:             name,
+		Mode:             mode,
+		ModTime:          modTime,
+		UncompressedSize: n,
+		CompressedSize:   a.cw.n - offset,
+		Offset:           offset,
+	})
+	return n, nil
+}
+
+// Entries returns every entry added so far, in the order Add was called.
+func (a *Writer) Entries() []Entry {
+	return a.entries
+}
+
+// Close appends the JSON-encoded central directory, footed by a fixed
+// 12-byte [length][cdTrailerMagic] record so Reader can find it by reading
+// backward from the end of the archive. It does not close the underlying
+// writer.
+func (a *Writer) Close() error {
+	body, err := json.Marshal(a.entries)
+	if err != nil {
+		return err
+	}
+	if _, err := a.cw.Write(body); err != nil {
+		return err
+	}
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(body)))
+	_, err = a.cw.Write(append(size[:], cdTrailerMagic...))
+	return err
+}
+
+// readSeekerAt adapts an io.ReadSeeker to io.ReaderAt by serializing
+// Seek+Read pairs under a mutex, the same way mztar's own readSeekerAt
+// does, so OpenReader can accept a plain *os.File or an HTTP range-backed
+// io.ReadSeeker alike.
+type readSeekerAt struct {
+	mu sync.Mutex
+	rs io.ReadSeeker
+}
+
+func (r *readSeekerAt) ReadAt(p []byte, off int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.rs.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return io.ReadFull(r.rs, p)
+}
+
+// Reader provides random access to the entries of an archive written by a
+// Writer, without decoding any entry up front.
+type Reader struct {
+	entries []Entry
+	ra      io.ReaderAt
+}
+
+// OpenReader loads the central directory trailing an archive written by a
+// Writer, given ra over the complete size bytes of it.
+func OpenReader(ra io.ReaderAt, size int64) (*Reader, error) {
+	if size < 12 {
+		return nil, fmt.Errorf("mzarchive: archive too small to contain a central directory")
+	}
+	var tail [12]byte
+	if _, err := ra.ReadAt(tail[:], size-12); err != nil {
+		return nil, err
+	}
+	if string(tail[4:]) != cdTrailerMagic {
+		return nil, fmt.Errorf("mzarchive: no central directory trailer found")
+	}
+	cdLen := int64(binary.LittleEndian.Uint32(tail[:4]))
+	cdOffset := size - 12 - cdLen
+	if cdOffset < 0 {
+		return nil, fmt.Errorf("mzarchive: central directory trailer reports length %d larger than archive", cdLen)
+	}
+
+	buf := make([]byte, cdLen)
+	if _, err := ra.ReadAt(buf, cdOffset); err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	if err := json.Unmarshal(buf, &entries); err != nil {
+		return nil, fmt.Errorf("mzarchive: invalid central directory: %w", err)
+	}
+	return &Reader{entries: entries, ra: ra}, nil
+}
+
+// OpenReaderSeeker is OpenReader for an io.ReadSeeker that doesn't already
+// implement io.ReaderAt (e.g. an HTTP range-backed reader, as returned by
+// the CLI's cmd/internal/shttp package); reads against it are serialized.
+func OpenReaderSeeker(rs io.ReadSeeker, size int64) (*Reader, error) {
+	return OpenReader(&readSeekerAt{rs: rs}, size)
+}
+
+// Entries returns every entry recorded in the central directory, in the
+// order they were added.
+func (a *Reader) Entries() []Entry {
+	return a.entries
+}
+
+func (a *Reader) find(name string) (Entry, error) {
+	for _, e := range a.entries {
+		if e.Name == name {
+			return e, nil
+		}
+	}
+	return Entry{}, fmt.Errorf("mzarchive: entry %q not found", name)
+}
+
+// Open returns a ReadCloser decoding the named entry's independent MinLZ
+// stream, fetching only the bytes that entry occupies.
+func (a *Reader) Open(name string) (io.ReadCloser, error) {
+	e, err := a.find(name)
+	if err != nil {
+		return nil, err
+	}
+	sr := io.NewSectionReader(a.ra, e.Offset, e.CompressedSize)
+	return io.NopCloser(minlz.NewReader(sr)), nil
+}
+
+// Extract decodes the named entry's content into w, using up to cpu threads
+// to decode its blocks concurrently (see minlz.Reader.DecodeConcurrent).
+// Since every entry is its own independent stream, Extract may safely be
+// called for several different entries at once from separate goroutines.
+func (a *Reader) Extract(name string, w io.Writer, cpu int) (int64, error) {
+	e, err := a.find(name)
+	if err != nil {
+		return 0, err
+	}
+	sr := io.NewSectionReader(a.ra, e.Offset, e.CompressedSize)
+	return minlz.NewReader(sr).DecodeConcurrent(w, cpu)
+}
@@ -0,0 +1,115 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mzarchive
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/minlz"
+)
+
+// TestRoundtrip writes a handful of entries, reopens the archive and checks
+// every entry's content, size and metadata come back unchanged, and that
+// both Open and Extract agree on the decoded bytes.
+func TestRoundtrip(t *testing.T) {
+	entries := map[string]string{
+		"a.txt":        "hello, world",
+		"empty.txt":    "",
+		"dir/b.bin":    strings.Repeat("x", 1<<16),
+		"dir/sub/c.md": "# title\n\nbody",
+	}
+	modTime := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, minlz.WriterLevel(minlz.LevelFastest))
+	for _, name := range []string{"a.txt", "empty.txt", "dir/b.bin", "dir/sub/c.md"} {
+		n, err := w.Add(name, 0o640, modTime, strings.NewReader(entries[name]))
+		if err != nil {
+			t.Fatalf("Add(%q): %v", name, err)
+		}
+		if got, want := n, int64(len(entries[name])); got != want {
+			t.Fatalf("Add(%q) uncompressed size = %d, want %d", name, got, want)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got, want := len(w.Entries()), len(entries); got != want {
+		t.Fatalf("Entries() has %d entries, want %d", got, want)
+	}
+
+	r, err := OpenReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("OpenReader: %v", err)
+	}
+	if got, want := len(r.Entries()), len(entries); got != want {
+		t.Fatalf("Entries() has %d entries, want %d", got, want)
+	}
+	for _, e := range r.Entries() {
+		want, ok := entries[e.Name]
+		if !ok {
+			t.Fatalf("unexpected entry %q", e.Name)
+		}
+		if e.Mode != 0o640 {
+			t.Fatalf("%s: mode = %v, want %v", e.Name, e.Mode, os.FileMode(0o640))
+		}
+		if !e.ModTime.Equal(modTime) {
+			t.Fatalf("%s: modtime = %v, want %v", e.Name, e.ModTime, modTime)
+		}
+
+		rc, err := r.Open(e.Name)
+		if err != nil {
+			t.Fatalf("Open(%q): %v", e.Name, err)
+		}
+		got, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("reading %q: %v", e.Name, err)
+		}
+		if string(got) != want {
+			t.Fatalf("%s: content mismatch, got %d bytes, want %d", e.Name, len(got), len(want))
+		}
+
+		var extracted bytes.Buffer
+		if _, err := r.Extract(e.Name, &extracted, 2); err != nil {
+			t.Fatalf("Extract(%q): %v", e.Name, err)
+		}
+		if extracted.String() != want {
+			t.Fatalf("%s: Extract content mismatch", e.Name)
+		}
+	}
+
+	if _, err := r.Open("does-not-exist"); err == nil {
+		t.Fatal("Open accepted a name not in the central directory")
+	}
+}
+
+// TestOpenReaderRejectsGarbage checks OpenReader's error paths: an input
+// too small to hold a trailer, and one whose trailer doesn't end in the
+// expected magic.
+func TestOpenReaderRejectsGarbage(t *testing.T) {
+	if _, err := OpenReader(bytes.NewReader([]byte("short")), 5); err == nil {
+		t.Fatal("OpenReader accepted an archive too small for a trailer")
+	}
+	garbage := bytes.Repeat([]byte{0}, 32)
+	if _, err := OpenReader(bytes.NewReader(garbage), int64(len(garbage))); err == nil {
+		t.Fatal("OpenReader accepted an archive with no central directory trailer")
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mztar splits a tar byte stream into two interleaved streams inside
+// a single .mz container, the way github.com/vbatts/tar-split does for gzip:
+// file payload bytes, which compress well and are encoded as ordinary MinLZ
+// blocks, and a "packer" stream of everything else a tar.Reader would
+// otherwise discard or reproduce approximately -- headers, PAX records,
+// padding and inter-entry gaps -- captured verbatim. Muxing the packer bytes
+// back in at the right offsets lets NewAssembler reproduce the original tar
+// byte-for-byte, which plain block compression followed by re-creating the
+// tar with archive/tar cannot guarantee (header field order, padding choices
+// and the like are not specified by the format).
+package mztar
+
+import (
+	"archive/tar"
+	"io"
+
+	"github.com/minio/minlz"
+)
+
+// chunkPacker carries raw tar bytes that aren't part of a file's content:
+// headers, PAX records, padding and gaps between entries. chunkPacker is
+// "skippable": a reader with no mztar support (a plain minlz.Reader) skips
+// over it like any other unknown user chunk, rather than failing.
+const chunkPacker = minlz.MinUserSkippableChunk
+
+// NewSplittingWriter returns an io.WriteCloser that expects a tar byte
+// stream to be written to it (typically via io.Copy from an archive/tar
+// writer, or from a source tarball read straight through). It disassembles
+// the stream into file content, compressed through a minlz.Writer configured
+// with opts, and packer bytes, muxed into w as chunkPacker user chunks in
+// their original stream position. Close must be called to flush the
+// underlying minlz stream; it also reports the first error seen while
+// parsing or writing, if any.
+func NewSplittingWriter(w io.Writer, opts ...minlz.WriterOption) io.WriteCloser {
+	pr, pw := io.Pipe()
+	mw := minlz.NewWriter(w, opts...)
+	sw := &splittingWriter{pw: pw, done: make(chan error, 1)}
+	go sw.run(pr, mw)
+	return sw
+}
+
+type splittingWriter struct {
+	pw   *io.PipeWriter
+	done chan error
+}
+
+func (s *splittingWriter) Write(p []byte) (int, error) {
+	return s.pw.Write(p)
+}
+
+// Close signals end of input, waits for the background split to finish
+// draining it, and closes the underlying minlz stream.
+func (s *splittingWriter) Close() error {
+	s.pw.Close()
+	return <-s.done
+}
+
+func (s *splittingWriter) run(pr *io.PipeReader, mw *minlz.Writer) {
+	err := split(pr, mw)
+	pr.CloseWithError(err)
+	if cerr := mw.Close(); err == nil {
+		err = cerr
+	}
+	s.done <- err
+}
+
+// split walks r as a tar stream, forwarding file content through mw's normal
+// block encoding and everything else through chunkPacker user chunks, in the
+// order it's encountered.
+func split(r io.Reader, mw *minlz.Writer) error {
+	cr := &capturingReader{r: r, capture: true}
+	tr := tar.NewReader(cr)
+	for {
+		cr.header = cr.header[:0]
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return flushPacker(mw, cr.header)
+		}
+		if err != nil {
+			return err
+		}
+		if err := flushPacker(mw, cr.header); err != nil {
+			return err
+		}
+		if hdr.Size <= 0 {
+			continue
+		}
+		cr.capture = false
+		_, err = io.Copy(writerFunc(mw.Write), io.LimitReader(tr, hdr.Size))
+		cr.capture = true
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func flushPacker(mw *minlz.Writer, b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	// WriteUserChunk writes directly to the underlying stream, bypassing mw's
+	// own block buffer, so any content still buffered from the previous entry
+	// must be flushed first or it ends up reordered after this chunk.
+	if err := mw.Flush(); err != nil {
+		return err
+	}
+	return mw.WriteUserChunk(chunkPacker, b)
+}
+
+// capturingReader wraps r, recording every byte it returns into header while
+// capture is true. Content bytes are read with capture temporarily false, so
+// they reach the caller (and, from there, the minlz block encoder) without
+// being duplicated into header.
+type capturingReader struct {
+	r       io.Reader
+	header  []byte
+	capture bool
+}
+
+func (c *capturingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if c.capture && n > 0 {
+		c.header = append(c.header, p[:n]...)
+	}
+	return n, err
+}
+
+// writerFunc adapts a Write method to io.Writer.
+type writerFunc func([]byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
+
+// NewAssembler returns an io.Reader that reproduces, byte-for-byte, the tar
+// stream a matching NewSplittingWriter split apart: it reads r as a minlz
+// stream built with opts, reassembling packer bytes and decoded file content
+// back into their original order.
+func NewAssembler(r io.Reader, opts ...minlz.ReaderOption) io.Reader {
+	a := &assembler{}
+	mr := minlz.NewReader(r, opts...)
+	mr.SetUserChunkHandler(func(id byte, cr io.Reader) error {
+		if id != chunkPacker {
+			return nil
+		}
+		b, err := io.ReadAll(cr)
+		if err != nil {
+			return err
+		}
+		a.queue = append(a.queue, b...)
+		return nil
+	})
+	a.r = mr
+	return a
+}
+
+// assembler reassembles a split stream. Every user-chunk callback invocation
+// happens, in stream order, before the minlz.Reader.Read call it was
+// triggered from returns any decoded block data -- so within one underlying
+// Read, packer bytes queued by the callback always precede that call's
+// decoded bytes. queue holds bytes already known to come first; saved holds
+// decoded bytes fetched during a Read that also grew queue, deferred until
+// queue has been drained so stream order is preserved across Read calls too.
+type assembler struct {
+	r     io.Reader
+	queue []byte
+	saved []byte
+	err   error
+}
+
+func (a *assembler) Read(p []byte) (int, error) {
+	if len(a.queue) > 0 {
+		n := copy(p, a.queue)
+		a.queue = a.queue[n:]
+		return n, nil
+	}
+	if len(a.saved) > 0 {
+		n := copy(p, a.saved)
+		a.saved = a.saved[n:]
+		if len(a.saved) == 0 && a.err != nil {
+			return n, a.err
+		}
+		return n, nil
+	}
+	if a.err != nil {
+		return 0, a.err
+	}
+
+	buf := make([]byte, len(p))
+	n, err := a.r.Read(buf)
+	if len(a.queue) > 0 {
+		// The callback queued packer bytes during this Read: they precede
+		// whatever it decoded, so stash the decoded bytes for later and let
+		// the caller drain queue first, on the next call.
+		if n > 0 {
+			a.saved = append(a.saved, buf[:n]...)
+		}
+		a.err = err
+		return a.Read(p)
+	}
+	if n > 0 {
+		a.err = err
+		copy(p, buf[:n])
+		return n, nil
+	}
+	return 0, err
+}
@@ -0,0 +1,292 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mztar
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/minio/minlz"
+)
+
+// tocTrailerMagic identifies the fixed footer NewTOCWriter appends after the
+// stream's own minlz.Index trailer, the way indexTrailer (in minlz's seek.go)
+// identifies that one: a 4-byte length followed by this magic, read from the
+// end of the file, locates the JSON table of contents without requiring any
+// of the stream to be decoded first.
+const tocTrailerMagic = "mztarToc"
+
+// TOCEntry records where one tar entry written by a TOCWriter landed, enough
+// for TOCReader.Open to fetch and decode only the block(s) covering it.
+type TOCEntry struct {
+	Name    string    `json:"name"`
+	Mode    int64     `json:"mode"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modtime"`
+
+	// BlockCompressedOffset and BlockUncompressedOffset are the same pair a
+	// minlz.Index entry records for the block this entry's tar header
+	// starts in: every entry begins its own block, so these also identify
+	// the block uniquely.
+	BlockCompressedOffset   int64 `json:"blockCompressedOffset"`
+	BlockUncompressedOffset int64 `json:"blockUncompressedOffset"`
+
+	// EntryOffsetInBlock is how many decoded bytes of tar header (and, for
+	// PAX/GNU long-name entries, the preceding extension records) come
+	// before this entry's content within the block.
+	EntryOffsetInBlock int64 `json:"entryOffsetInBlock"`
+
+	// Digest is the hex-encoded SHA-256 of the entry's content.
+	Digest string `json:"digest"`
+}
+
+// countingWriter tracks how many bytes have reached the underlying
+// io.Writer, so NewTOCWriter can record each entry's compressed offset as it
+// writes, mirroring minlz.SeekableWriter's own countingWriter.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// teeWriter wraps r, forwarding every byte it returns to mw.Write and
+// counting it, so buildTOC can learn both the tar stream's parse position
+// (via n) and get the bytes into the minlz stream in the same pass, without
+// a second read of the content archive/tar already consumed.
+type teeWriter struct {
+	r  io.Reader
+	mw *minlz.Writer
+	n  int64
+}
+
+func (t *teeWriter) Read(p []byte) (int, error) {
+	n, err := t.r.Read(p)
+	if n > 0 {
+		if _, werr := t.mw.Write(p[:n]); werr != nil {
+			return n, werr
+		}
+		t.n += int64(n)
+	}
+	return n, err
+}
+
+// NewTOCWriter returns an io.WriteCloser that expects a tar byte stream to
+// be written to it, the way NewSplittingWriter does. Instead of
+// reconstructing the tar byte-for-byte, it starts a fresh MinLZ block at
+// every tar entry boundary and, on Close, appends the stream's own
+// minlz.Index followed by a JSON table of contents (see TOCEntry) of where
+// each entry landed -- opts' WriterSeekable setting is overridden to true, since
+// TOCReader needs that index to locate a block. Call TOC after Close to
+// retrieve the entries that were recorded.
+func NewTOCWriter(w io.Writer, opts ...minlz.WriterOption) *TOCWriter {
+	cw := &countingWriter{w: w}
+	mw := minlz.NewWriter(cw, append(append([]minlz.WriterOption{}, opts...), minlz.WriterSeekable(true))...)
+	pr, pw := io.Pipe()
+	t := &TOCWriter{pw: pw, done: make(chan error, 1)}
+	go t.run(pr, mw, cw)
+	return t
+}
+
+// TOCWriter is returned by NewTOCWriter.
+type TOCWriter struct {
+	pw   *io.PipeWriter
+	done chan error
+	toc  []TOCEntry
+}
+
+func (t *TOCWriter) Write(p []byte) (int, error) {
+	return t.pw.Write(p)
+}
+
+// Close signals end of input, waits for the background parse to finish, and
+// closes the underlying minlz stream and its trailing table of contents.
+func (t *TOCWriter) Close() error {
+	t.pw.Close()
+	return <-t.done
+}
+
+// TOC returns the entries recorded so far, in tar order. It's only complete
+// once Close has returned successfully.
+func (t *TOCWriter) TOC() []TOCEntry {
+	return t.toc
+}
+
+func (t *TOCWriter) run(pr *io.PipeReader, mw *minlz.Writer, cw *countingWriter) {
+	toc, err := buildTOC(pr, mw, cw)
+	t.toc = toc
+	pr.CloseWithError(err)
+	if cerr := mw.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = writeTOCTrailer(cw, toc)
+	}
+	t.done <- err
+}
+
+// buildTOC walks r as a tar stream, forwarding every byte read (header,
+// extension records and content alike) through mw in the order it's
+// encountered, flushing mw before each entry so it starts its own block.
+func buildTOC(r io.Reader, mw *minlz.Writer, cw *countingWriter) ([]TOCEntry, error) {
+	tw := &teeWriter{r: r, mw: mw}
+	tr := tar.NewReader(tw)
+	var toc []TOCEntry
+	for {
+		if err := mw.Flush(); err != nil {
+			return toc, err
+		}
+		blockComp, blockUncomp := cw.n, tw.n
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return toc, nil
+		}
+		if err != nil {
+			return toc, err
+		}
+		entryOffset := tw.n - blockUncomp
+
+		h := sha256.New()
+		if hdr.Size > 0 {
+			if _, err := io.Copy(h, io.LimitReader(tr, hdr.Size)); err != nil {
+				return toc, err
+			}
+		}
+		toc = append(toc, TOCEntry{
+			Name:                    hdr.Name,
+			Mode:                    hdr.Mode,
+			Size:                    hdr.Size,
+			ModTime:                 hdr.ModTime,
+			BlockCompressedOffset:   blockComp,
+			BlockUncompressedOffset: blockUncomp,
+			EntryOffsetInBlock:      entryOffset,
+			Digest:                  hex.EncodeToString(h.Sum(nil)),
+		})
+	}
+}
+
+// writeTOCTrailer appends the JSON-encoded toc directly to cw, after
+// mw.Close has written the stream's own EOF chunk and index trailer, footed
+// by a fixed 12-byte [length][tocTrailerMagic] record so TOCReader can find
+// it by reading backward from the end of the file.
+func writeTOCTrailer(cw *countingWriter, toc []TOCEntry) error {
+	body, err := json.Marshal(toc)
+	if err != nil {
+		return err
+	}
+	if _, err := cw.Write(body); err != nil {
+		return err
+	}
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(body)))
+	_, err = cw.Write(append(size[:], tocTrailerMagic...))
+	return err
+}
+
+// readSeekerAt adapts an io.ReadSeeker to io.ReaderAt by serializing
+// Seek+Read pairs under a mutex, the same way minlz's own unexported
+// readSeekerAt does for NewSeekableReader -- needed here so OpenTOCReader
+// can accept a plain *os.File or an HTTP range-backed io.ReadSeeker alike.
+type readSeekerAt struct {
+	mu sync.Mutex
+	rs io.ReadSeeker
+}
+
+func (r *readSeekerAt) ReadAt(p []byte, off int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.rs.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return io.ReadFull(r.rs, p)
+}
+
+// TOCReader provides random access to entries written by a TOCWriter,
+// without requiring any of the stream's content to be decoded up front.
+type TOCReader struct {
+	toc []TOCEntry
+	sr  *minlz.SeekableReader
+}
+
+// OpenTOCReader loads the table of contents and index trailing a stream
+// written by NewTOCWriter, given r over the complete size bytes of it. r
+// only needs to support Seek; reads against it are serialized, so a remote
+// io.ReadSeeker backed by HTTP range requests (see the cmd/internal/shttp
+// package the CLI uses) works as well as a local file, fetching only the
+// small footer and whichever blocks Open later asks for.
+func OpenTOCReader(r io.ReadSeeker, size int64) (*TOCReader, error) {
+	if size < 12 {
+		return nil, fmt.Errorf("mztar: stream too small to contain a table of contents")
+	}
+	ra := &readSeekerAt{rs: r}
+
+	var tail [12]byte
+	if _, err := ra.ReadAt(tail[:], size-12); err != nil {
+		return nil, err
+	}
+	if string(tail[4:]) != tocTrailerMagic {
+		return nil, fmt.Errorf("mztar: no table of contents trailer found")
+	}
+	tocLen := int64(binary.LittleEndian.Uint32(tail[:4]))
+	streamSize := size - 12 - tocLen
+	if streamSize < 0 {
+		return nil, fmt.Errorf("mztar: table of contents trailer reports length %d larger than stream", tocLen)
+	}
+
+	buf := make([]byte, tocLen)
+	if _, err := ra.ReadAt(buf, streamSize); err != nil {
+		return nil, err
+	}
+	var toc []TOCEntry
+	if err := json.Unmarshal(buf, &toc); err != nil {
+		return nil, fmt.Errorf("mztar: invalid table of contents: %w", err)
+	}
+
+	sr, err := minlz.OpenSeekableReader(ra, streamSize)
+	if err != nil {
+		return nil, err
+	}
+	return &TOCReader{toc: toc, sr: sr}, nil
+}
+
+// Entries returns every entry recorded in the table of contents, in the
+// order they appeared in the original tar stream.
+func (t *TOCReader) Entries() []TOCEntry {
+	return t.toc
+}
+
+// Open returns the decoded content of the entry named name, fetching and
+// decoding only the MinLZ block(s) that cover it.
+func (t *TOCReader) Open(name string) (io.ReadCloser, error) {
+	for _, e := range t.toc {
+		if e.Name != name {
+			continue
+		}
+		off := e.BlockUncompressedOffset + e.EntryOffsetInBlock
+		return io.NopCloser(io.NewSectionReader(t.sr, off, e.Size)), nil
+	}
+	return nil, fmt.Errorf("mztar: entry %q not found", name)
+}
@@ -0,0 +1,218 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mztar
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/minio/minlz"
+)
+
+// TestTOCRoundtrip writes a small tarball through a TOCWriter, then opens
+// each entry by name through a TOCReader and checks its content and
+// recorded digest, for the USTAR and PAX cases TestRoundtrip also covers.
+func TestTOCRoundtrip(t *testing.T) {
+	longName := "a/very/deeply/nested/path/" + string(bytes.Repeat([]byte("x"), 150)) + "/file.txt"
+
+	cases := []struct {
+		name    string
+		format  tar.Format
+		entries map[string]string
+	}{
+		{
+			name:   "ustar",
+			format: tar.FormatUSTAR,
+			entries: map[string]string{
+				"a.txt": "hello, world",
+				"b.txt": "",
+				"c.bin": string(bytes.Repeat([]byte{0xab, 0xcd}, 1000)),
+			},
+		},
+		{
+			name:   "pax",
+			format: tar.FormatPAX,
+			entries: map[string]string{
+				"short":    "a",
+				longName:   "content behind a long name",
+				"exact512": string(bytes.Repeat([]byte("y"), 512)),
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			src := buildTar(t, tc.format, tc.entries)
+
+			var mz bytes.Buffer
+			tw := NewTOCWriter(&mz, minlz.WriterLevel(minlz.LevelFastest))
+			if _, err := tw.Write(src); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+			if err := tw.Close(); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+
+			if got, want := len(tw.TOC()), len(tc.entries); got != want {
+				t.Fatalf("TOC has %d entries, want %d", got, want)
+			}
+
+			tr, err := OpenTOCReader(bytes.NewReader(mz.Bytes()), int64(mz.Len()))
+			if err != nil {
+				t.Fatalf("OpenTOCReader: %v", err)
+			}
+			if got, want := len(tr.Entries()), len(tc.entries); got != want {
+				t.Fatalf("Entries() has %d entries, want %d", got, want)
+			}
+
+			for _, e := range tr.Entries() {
+				want, ok := tc.entries[e.Name]
+				if !ok {
+					t.Fatalf("unexpected entry %q in TOC", e.Name)
+				}
+				wantSum := sha256.Sum256([]byte(want))
+				if e.Digest != hex.EncodeToString(wantSum[:]) {
+					t.Errorf("%s: digest mismatch: got %s, want %x", e.Name, e.Digest, wantSum)
+				}
+
+				rc, err := tr.Open(e.Name)
+				if err != nil {
+					t.Fatalf("Open(%s): %v", e.Name, err)
+				}
+				got, err := io.ReadAll(rc)
+				rc.Close()
+				if err != nil {
+					t.Fatalf("ReadAll(%s): %v", e.Name, err)
+				}
+				if string(got) != want {
+					t.Errorf("%s: content mismatch: got %q, want %q", e.Name, got, want)
+				}
+			}
+
+			if _, err := tr.Open("does-not-exist"); err == nil {
+				t.Fatal("Open of a missing entry should fail")
+			}
+		})
+	}
+}
+
+// httpRangeReadSeeker is a minimal io.ReadSeeker over an object served by an
+// http.Server that honors Range requests, counting every byte its Read calls
+// actually receive so TestTOCOverHTTP can check how much of a large archive
+// a single Open ends up fetching.
+type httpRangeReadSeeker struct {
+	url   string
+	pos   int64
+	total int64
+	read  int64
+}
+
+func (h *httpRangeReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+		h.pos = offset
+	case io.SeekCurrent:
+		h.pos += offset
+	case io.SeekEnd:
+		h.pos = h.total + offset
+	}
+	return h.pos, nil
+}
+
+func (h *httpRangeReadSeeker) Read(p []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodGet, h.url, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", h.pos, h.pos+int64(len(p))-1))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusPartialContent {
+		return 0, fmt.Errorf("range request returned status %s", resp.Status)
+	}
+	n, err := io.ReadFull(resp.Body, p)
+	h.pos += int64(n)
+	h.read += int64(n)
+	return n, err
+}
+
+// TestTOCOverHTTP serves a .mz built by a TOCWriter from a many-entry
+// tarball over a Range-capable httptest.Server, and checks that opening a
+// single small entry only downloads a small multiple of its size plus the
+// footer -- not the whole object -- the point of giving it a table of
+// contents in the first place.
+func TestTOCOverHTTP(t *testing.T) {
+	entries := map[string]string{}
+	for i := 0; i < 200; i++ {
+		entries[fmt.Sprintf("file-%03d.bin", i)] = string(bytes.Repeat([]byte{byte(i)}, 4096))
+	}
+	const needle = "file-150.bin"
+	src := buildTar(t, tar.FormatUSTAR, entries)
+
+	var mz bytes.Buffer
+	tw := NewTOCWriter(&mz, minlz.WriterLevel(minlz.LevelFastest), minlz.WriterBlockSize(4096))
+	if _, err := tw.Write(src); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	raw := mz.Bytes()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		http.ServeContent(rw, req, "", time.Time{}, bytes.NewReader(raw))
+	}))
+	defer srv.Close()
+
+	rs := &httpRangeReadSeeker{url: srv.URL, total: int64(len(raw))}
+	tr, err := OpenTOCReader(rs, rs.total)
+	if err != nil {
+		t.Fatalf("OpenTOCReader: %v", err)
+	}
+	afterOpen := rs.read
+
+	rc, err := tr.Open(needle)
+	if err != nil {
+		t.Fatalf("Open(%s): %v", needle, err)
+	}
+	got, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		t.Fatalf("ReadAll(%s): %v", needle, err)
+	}
+	want := entries[needle]
+	if string(got) != want {
+		t.Fatalf("content mismatch for %s", needle)
+	}
+
+	fetchedForEntry := rs.read - afterOpen
+	if max := int64(3 * len(want)); fetchedForEntry > max {
+		t.Errorf("fetching one %d-byte entry read %d bytes over the wire, want at most %d", len(want), fetchedForEntry, max)
+	}
+	if rs.read >= rs.total {
+		t.Errorf("fetching one entry read %d of %d total bytes, expected far less than the whole object", rs.read, rs.total)
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestWriterPadding(t *testing.T) {
+	for _, n := range []int{512, 4096, 1 << 20} {
+		var buf bytes.Buffer
+		w := NewWriter(&buf, WriterPadding(n))
+		data := []byte("some data to pad the output of")
+		if _, err := w.Write(data); err != nil {
+			t.Fatalf("n=%d: Write: %v", n, err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("n=%d: Close: %v", n, err)
+		}
+		if buf.Len()%n != 0 {
+			t.Fatalf("n=%d: encoded length %d is not a multiple of %d", n, buf.Len(), n)
+		}
+		got, err := io.ReadAll(NewReader(bytes.NewReader(buf.Bytes())))
+		if err != nil {
+			t.Fatalf("n=%d: Read: %v", n, err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Fatalf("n=%d: roundtrip mismatch", n)
+		}
+	}
+}
+
+func TestWriterPaddingInvalid(t *testing.T) {
+	for _, n := range []int{0, -1, 3, 100, maxPaddingChunkSize + 1} {
+		w := NewWriter(nil, WriterPadding(n))
+		if _, err := w.Write([]byte("x")); err == nil {
+			t.Fatalf("n=%d: want error, got nil", n)
+		}
+	}
+}
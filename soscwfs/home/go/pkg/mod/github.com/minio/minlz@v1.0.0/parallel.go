@@ -0,0 +1,398 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// WithConcurrency sets the number of goroutines used to compress blocks.
+// With n > 1, blocks are encoded in parallel but still written to the
+// underlying io.Writer strictly in submission order, so the resulting
+// stream is byte-for-byte identical to what a single-threaded Writer would
+// produce.
+func WithConcurrency(n int) WriterOption {
+	return func(w *Writer) error {
+		if n < 1 {
+			n = 1
+		}
+		w.concurrency = n
+		return nil
+	}
+}
+
+// WithBlockSize is an alias for WriterBlockSize, kept for callers migrating
+// from NewParallelWriter.
+func WithBlockSize(n int) WriterOption {
+	return WriterBlockSize(n)
+}
+
+// WriterConcurrency is an alias for WithConcurrency, named to pair with
+// ReaderConcurrency on the Reader side.
+func WriterConcurrency(n int) WriterOption {
+	return WithConcurrency(n)
+}
+
+// NewParallelWriter is a convenience constructor equivalent to
+// NewWriter(w, append([]WriterOption{WithConcurrency(defaultParallelism)}, opts...)...),
+// i.e. it defaults to 4 concurrent blocks. Pass WithConcurrency explicitly
+// in opts to override the default.
+func NewParallelWriter(w io.Writer, opts ...WriterOption) *Writer {
+	all := make([]WriterOption, 0, len(opts)+1)
+	all = append(all, WithConcurrency(defaultParallelism))
+	all = append(all, opts...)
+	return NewWriter(w, all...)
+}
+
+type parallelJob struct {
+	seq   uint64
+	block []byte
+}
+
+type parallelResult struct {
+	seq             uint64
+	chunk           []byte // fully framed chunk (type+len header included), or nil on err
+	uncompressedLen int
+	err             error
+}
+
+// parallelPool dispatches blocks to a fixed worker pool and serializes the
+// resulting framed chunks back to the Writer in submission order.
+type parallelPool struct {
+	w    *Writer
+	jobs chan parallelJob
+	wg   sync.WaitGroup
+
+	mu      sync.Mutex
+	pending map[uint64]parallelResult
+	next    uint64
+	err     error
+	flushed sync.Cond
+}
+
+func newParallelPool(w *Writer, n int) *parallelPool {
+	p := &parallelPool{
+		w:       w,
+		jobs:    make(chan parallelJob, n*2),
+		pending: make(map[uint64]parallelResult),
+	}
+	p.flushed.L = &p.mu
+	for i := 0; i < n; i++ {
+		p.wg.Add(1)
+		go p.worker()
+	}
+	return p
+}
+
+func (p *parallelPool) worker() {
+	defer p.wg.Done()
+	for job := range p.jobs {
+		chunk, err := p.w.encodeFramedBlock(job.block)
+		p.mu.Lock()
+		p.pending[job.seq] = parallelResult{seq: job.seq, chunk: chunk, uncompressedLen: len(job.block), err: err}
+		p.flushed.Broadcast()
+		p.mu.Unlock()
+	}
+}
+
+// submit hands a block to the pool. It may block if the job queue is full,
+// providing backpressure against slow writers.
+func (p *parallelPool) submit(seq uint64, block []byte) {
+	p.jobs <- parallelJob{seq: seq, block: block}
+}
+
+// drainReady writes every in-order result that has become available,
+// blocking until at least one write happens if wait is true.
+func (p *parallelPool) drainReady(wait bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for {
+		res, ok := p.pending[p.next]
+		if !ok {
+			if !wait || p.err != nil {
+				return p.err
+			}
+			p.flushed.Wait()
+			continue
+		}
+		delete(p.pending, p.next)
+		p.next++
+		if res.err != nil {
+			if p.err == nil {
+				p.err = res.err
+			}
+			return p.err
+		}
+		if p.w.seekable {
+			p.w.recordIndexEntry(res.uncompressedLen)
+		}
+		n, err := p.w.w.Write(res.chunk)
+		p.w.written += int64(n)
+		if err != nil {
+			if p.err == nil {
+				p.err = err
+			}
+			return p.err
+		}
+		if !wait {
+			// Try the next one too, but don't block.
+			continue
+		}
+		return nil
+	}
+}
+
+// closeAndWait stops accepting jobs, waits for all workers to finish, and
+// flushes every remaining in-order result.
+func (p *parallelPool) closeAndWait() error {
+	close(p.jobs)
+	p.wg.Wait()
+	for {
+		p.mu.Lock()
+		_, ok := p.pending[p.next]
+		p.mu.Unlock()
+		if !ok {
+			break
+		}
+		if err := p.drainReady(false); err != nil {
+			return err
+		}
+	}
+	return p.err
+}
+
+// encodeFramedBlock runs the same encode path flushBlock uses, but returns
+// the framed chunk bytes instead of writing them, so it is safe to call
+// concurrently from multiple worker goroutines (each gets its own dst).
+func (w *Writer) encodeFramedBlock(block []byte) ([]byte, error) {
+	tableBits := defaultTableBits
+	if w.lowMem {
+		tableBits = lowMemTableBits
+	}
+	var enc []byte
+	var err error
+	if w.dict != nil {
+		enc, err = encodeDictTableBits(nil, block, w.dict.bytes, w.level, tableBits)
+	} else {
+		enc, err = encodeTableBits(nil, block, w.level, tableBits)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var digest []byte
+	if w.digestAlg != DigestNone {
+		digest, err = computeDigest(w.digestAlg, block)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !w.crc {
+		return frameChunk(chunkMinLZBlock, append(enc, digest...)), nil
+	}
+	payload := make([]byte, 4+len(enc)+len(digest))
+	putChecksum(payload, block)
+	copy(payload[4:], enc)
+	copy(payload[4+len(enc):], digest)
+	return frameChunk(chunkMinLZCompCRC, payload), nil
+}
+
+const defaultParallelism = 4
+
+// decodeJob is a unit of work submitted to a decodePool: a frame whose
+// payload still needs decoding (typ/data set). Control chunks the feeder
+// goroutine handles inline are not submitted as jobs; their results are
+// published directly.
+type decodeJob struct {
+	seq          uint64
+	typ          byte
+	data         []byte
+	dict         []byte
+	requireCRC   bool
+	digestAlg    DigestAlg
+	verifyDigest bool
+}
+
+type decodeResult struct {
+	block     []byte
+	err       error
+	hasResult bool // true once a worker (or the feeder, for control chunks) has produced a result
+}
+
+// decodePool dispatches frame payloads to a fixed worker pool and lets the
+// Reader pull results back in stream order via next, regardless of which
+// worker finishes first.
+type decodePool struct {
+	jobs chan decodeJob
+	wg   sync.WaitGroup
+
+	mu      sync.Mutex
+	pending map[uint64]decodeResult
+	wantSeq uint64
+	cond    sync.Cond
+}
+
+func newDecodePool(n int) *decodePool {
+	p := &decodePool{
+		jobs:    make(chan decodeJob, n*2),
+		pending: make(map[uint64]decodeResult),
+	}
+	p.cond.L = &p.mu
+	for i := 0; i < n; i++ {
+		p.wg.Add(1)
+		go p.worker()
+	}
+	return p
+}
+
+func (p *decodePool) worker() {
+	defer p.wg.Done()
+	for job := range p.jobs {
+		block, err := decodeFrameBlock(job.typ, job.data, job.dict, job.requireCRC, job.digestAlg, job.verifyDigest)
+		p.publish(job.seq, block, err)
+	}
+}
+
+// publish records the result for seq, whether it came from a worker or was
+// resolved inline by the feeder for a control chunk.
+func (p *decodePool) publish(seq uint64, block []byte, err error) {
+	p.mu.Lock()
+	p.pending[seq] = decodeResult{block: block, err: err, hasResult: true}
+	p.cond.Broadcast()
+	p.mu.Unlock()
+}
+
+// submit hands a frame payload to the pool for decoding.
+func (p *decodePool) submit(seq uint64, typ byte, data []byte, dict []byte, requireCRC bool, digestAlg DigestAlg, verifyDigest bool) {
+	p.jobs <- decodeJob{seq: seq, typ: typ, data: data, dict: dict, requireCRC: requireCRC, digestAlg: digestAlg, verifyDigest: verifyDigest}
+}
+
+// next blocks until the result for the next sequence number (in increasing
+// order, starting at 0) is available, and returns it.
+func (p *decodePool) next() (block []byte, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for {
+		res, ok := p.pending[p.wantSeq]
+		if ok && res.hasResult {
+			delete(p.pending, p.wantSeq)
+			p.wantSeq++
+			return res.block, res.err
+		}
+		p.cond.Wait()
+	}
+}
+
+// feedParallel runs on its own goroutine, reading chunk headers from the
+// Reader sequentially (the only safe way, since io.Reader is not
+// concurrency-safe) and either dispatching frame payloads to decodePool
+// workers or resolving control chunks immediately.
+func (r *Reader) feedParallel() {
+	var seq uint64
+	for {
+		typ, data, err := r.readChunk()
+		if err != nil {
+			r.dpool.publish(seq, nil, err)
+			return
+		}
+		switch {
+		case typ == chunkStreamID:
+			if string(data) != streamMagicBody {
+				r.dpool.publish(seq, nil, ErrCorrupt)
+				return
+			}
+			r.dpool.publish(seq, nil, nil)
+		case typ == chunkDictID:
+			r.dpool.publish(seq, nil, r.checkDictID(data))
+		case typ == chunkDigestAlg:
+			if len(data) != 1 {
+				r.dpool.publish(seq, nil, ErrCorrupt)
+				return
+			}
+			r.digestAlg = DigestAlg(data[0])
+			r.dpool.publish(seq, nil, nil)
+		case typ == chunkMinLZCompCRC, typ == chunkMinLZBlock, typ == chunkUncompressed:
+			r.dpool.submit(seq, typ, data, r.dictBytes(), r.requireCRC, r.digestAlg, r.verifyDigest)
+		case typ == chunkEOF:
+			// Multiple streams may be concatenated; keep reading.
+			r.dpool.publish(seq, nil, nil)
+		case typ <= maxNonSkippableChunk:
+			r.dpool.publish(seq, nil, fmt.Errorf("minlz: unknown non-skippable chunk %#x", typ))
+			return
+		case typ >= minUserSkippableChunk && typ <= maxUserNonSkippableChunk && r.userChunk != nil:
+			err := r.userChunk(typ, bytes.NewReader(data))
+			r.dpool.publish(seq, nil, err)
+			if err != nil {
+				return
+			}
+		default:
+			// Skippable chunk with no handler installed; ignore.
+			r.dpool.publish(seq, nil, nil)
+		}
+		seq++
+	}
+}
+
+// DecodeConcurrent decodes the entire stream, writing every decoded block to
+// dst in original order, using workers goroutines to decode blocks
+// concurrently (see nextParallel/decodePool). decodePool's buffered job
+// queue holds at most 2*workers blocks waiting to be decoded; decoded blocks
+// waiting for an earlier, slower block are kept without a limit. The first
+// decode error encountered stops the drain and is returned once
+// every block ahead of it has been written. It returns the total number of
+// bytes written to dst.
+func (r *Reader) DecodeConcurrent(dst io.Writer, workers int) (int64, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	r.concurrency = workers
+	var total int64
+	for {
+		block, err := r.nextParallel()
+		if err != nil {
+			if err == io.EOF {
+				return total, nil
+			}
+			return total, err
+		}
+		n, err := dst.Write(block)
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+}
+
+// nextParallel returns the next decoded block, starting the feeder goroutine
+// and worker pool on first use.
+func (r *Reader) nextParallel() ([]byte, error) {
+	if r.dpool == nil {
+		r.dpool = newDecodePool(r.concurrency)
+		go r.feedParallel()
+	}
+	for {
+		block, err := r.dpool.next()
+		if err != nil {
+			return nil, err
+		}
+		if block != nil {
+			return block, nil
+		}
+		// Control chunk; keep pulling results until a real block shows up.
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+// TestStreamConcurrentRoundtrip checks that WriterConcurrency(4) round-trips
+// through ReaderConcurrency(4) and produces output identical in length (and,
+// since block boundaries and content are deterministic, byte-for-byte) to
+// WriterConcurrency(1).
+func TestStreamConcurrentRoundtrip(t *testing.T) {
+	data := bytes.Repeat([]byte("the quick brown fox jumps over the lazy dog "), 20000)
+
+	var serial bytes.Buffer
+	w1 := NewWriter(&serial, WriterConcurrency(1), WriterBlockSize(16<<10))
+	if _, err := w1.Write(data); err != nil {
+		t.Fatalf("serial Write: %v", err)
+	}
+	if err := w1.Close(); err != nil {
+		t.Fatalf("serial Close: %v", err)
+	}
+
+	var parallel bytes.Buffer
+	w4 := NewWriter(&parallel, WriterConcurrency(4), WriterBlockSize(16<<10))
+	if _, err := w4.Write(data); err != nil {
+		t.Fatalf("parallel Write: %v", err)
+	}
+	if err := w4.Close(); err != nil {
+		t.Fatalf("parallel Close: %v", err)
+	}
+
+	if serial.Len() != parallel.Len() {
+		t.Fatalf("serial/parallel encoded length mismatch: %d vs %d", serial.Len(), parallel.Len())
+	}
+	if !bytes.Equal(serial.Bytes(), parallel.Bytes()) {
+		t.Fatalf("serial/parallel encoded bytes differ")
+	}
+
+	r := NewReader(bytes.NewReader(parallel.Bytes()), ReaderConcurrency(4))
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReaderConcurrency(4) Read: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("ReaderConcurrency(4) roundtrip mismatch")
+	}
+}
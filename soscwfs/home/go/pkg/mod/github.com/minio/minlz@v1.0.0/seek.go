@@ -0,0 +1,574 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+const (
+	indexHeader     = "minlzidx"
+	indexTrailer    = "xdizlnim"
+	maxIndexEntries = 1 << 16
+)
+
+// Index maps uncompressed byte ranges to the compressed block that holds
+// them, letting a SeekableReader jump directly to the block covering a
+// requested offset instead of decoding the stream from the start. Find
+// returns the nearest preceding entry; SeekableReader decodes only the block
+// at that entry, so it needs an entry for every block.
+type Index struct {
+	TotalUncompressed int64 // Total uncompressed size, or -1 if unknown.
+	TotalCompressed   int64 // Total compressed size, or -1 if unknown.
+
+	// DigestAlg is the strong per-block digest (see WriterDigest) recorded
+	// alongside each block, or DigestNone if the stream only carries the
+	// regular CRC32C checksum. A SeekableReader verifies it, in addition to
+	// the checksum, whenever it's not DigestNone.
+	DigestAlg DigestAlg
+
+	// Offsets maps compressed block starts to their uncompressed position,
+	// in increasing order of both fields.
+	Offsets []struct {
+		CompressedOffset   int64
+		UncompressedOffset int64
+	}
+
+	estBlockUncomp int64
+}
+
+// reset discards any existing entries and records the expected uncompressed
+// block size, which appendTo stores in the serialized index.
+func (i *Index) reset(estBlockUncomp int64) {
+	*i = Index{TotalCompressed: -1, TotalUncompressed: -1, estBlockUncomp: estBlockUncomp}
+}
+
+// add records the start of a block, which must be strictly past every
+// previously added block in both the compressed and uncompressed space.
+func (i *Index) add(uncompressedOffset, compressedOffset int64) error {
+	if len(i.Offsets) >= maxIndexEntries {
+		return fmt.Errorf("minlz: index already has the maximum %d entries", maxIndexEntries)
+	}
+	if n := len(i.Offsets); n > 0 {
+		prev := i.Offsets[n-1]
+		if uncompressedOffset <= prev.UncompressedOffset {
+			return fmt.Errorf("minlz: uncompressed offset %d not after previous entry %d", uncompressedOffset, prev.UncompressedOffset)
+		}
+		if compressedOffset <= prev.CompressedOffset {
+			return fmt.Errorf("minlz: compressed offset %d not after previous entry %d", compressedOffset, prev.CompressedOffset)
+		}
+	}
+	i.Offsets = append(i.Offsets, struct {
+		CompressedOffset   int64
+		UncompressedOffset int64
+	}{compressedOffset, uncompressedOffset})
+	return nil
+}
+
+// Find returns the compressed offset to seek to, and the uncompressed
+// offset the block found there starts at, for the block covering
+// uncompressed position off. A caller decodes from compressedOff and
+// discards (off - uncompressedOff) leading decoded bytes.
+func (i *Index) Find(off int64) (compressedOff, uncompressedOff int64, err error) {
+	if off < 0 {
+		return 0, 0, fmt.Errorf("minlz: negative offset %d", off)
+	}
+	if i.TotalUncompressed >= 0 && off > i.TotalUncompressed {
+		return 0, 0, fmt.Errorf("minlz: offset %d beyond stream size %d", off, i.TotalUncompressed)
+	}
+	n := sort.Search(len(i.Offsets), func(n int) bool {
+		return i.Offsets[n].UncompressedOffset > off
+	})
+	n--
+	if n < 0 {
+		return 0, 0, nil
+	}
+	return i.Offsets[n].CompressedOffset, i.Offsets[n].UncompressedOffset, nil
+}
+
+// AppendTo serializes idx in the same self-contained format SeekableWriter
+// appends to the end of a stream, and appends it to dst. LoadIndex parses
+// the result back given its total length, whether it was embedded in a
+// stream or, as cmd/mz's `index build` subcommand does for streams written
+// without a footer, saved standalone as a `.mz.idx` sidecar file.
+func (i *Index) AppendTo(dst []byte) []byte {
+	return i.appendTo(dst)
+}
+
+// appendTo serializes the index, as a framed minlz chunk with a trailing
+// magic + total-length footer, so LoadIndex can find and parse it by
+// reading backward from the end of a file without a full scan.
+func (i *Index) appendTo(dst []byte) []byte {
+	start := len(dst)
+	body := []byte(indexHeader)
+	body = binary.AppendVarint(body, i.TotalUncompressed)
+	body = binary.AppendVarint(body, i.TotalCompressed)
+	body = binary.AppendVarint(body, i.estBlockUncomp)
+	body = binary.AppendVarint(body, int64(i.DigestAlg))
+	body = binary.AppendVarint(body, int64(len(i.Offsets)))
+	for _, e := range i.Offsets {
+		body = binary.AppendVarint(body, e.UncompressedOffset)
+		body = binary.AppendVarint(body, e.CompressedOffset)
+	}
+	dst = append(dst, frameChunk(chunkIndex, body)...)
+	dst = append(dst, indexTrailer...)
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(dst)-start))
+	return append(dst, size[:]...)
+}
+
+// LoadIndex locates and parses the index trailer written by
+// SeekableWriter.Close at the end of a stream of the given size.
+func LoadIndex(r io.ReaderAt, size int64) (*Index, error) {
+	if size < 12 {
+		return nil, fmt.Errorf("minlz: stream too small to contain an index")
+	}
+	var tail [12]byte
+	if _, err := r.ReadAt(tail[:], size-12); err != nil {
+		return nil, err
+	}
+	if string(tail[0:8]) != indexTrailer {
+		return nil, fmt.Errorf("minlz: no index trailer found")
+	}
+	total := int64(binary.LittleEndian.Uint32(tail[8:12]))
+	if total > size {
+		return nil, fmt.Errorf("minlz: index trailer reports length %d larger than stream", total)
+	}
+	buf := make([]byte, total)
+	if _, err := r.ReadAt(buf, size-total-4); err != nil {
+		return nil, err
+	}
+	if buf[0] != chunkIndex {
+		return nil, fmt.Errorf("minlz: expected index chunk, got type %#x", buf[0])
+	}
+	chunkLen := int(buf[1]) | int(buf[2])<<8 | int(buf[3])<<16
+	body := buf[4 : 4+chunkLen]
+	if len(body) < len(indexHeader) || string(body[:len(indexHeader)]) != indexHeader {
+		return nil, fmt.Errorf("minlz: invalid index header")
+	}
+	body = body[len(indexHeader):]
+
+	readVarint := func() (int64, error) {
+		v, n := binary.Varint(body)
+		if n <= 0 {
+			return 0, fmt.Errorf("minlz: truncated index")
+		}
+		body = body[n:]
+		return v, nil
+	}
+
+	idx := &Index{}
+	var err error
+	if idx.TotalUncompressed, err = readVarint(); err != nil {
+		return nil, err
+	}
+	if idx.TotalCompressed, err = readVarint(); err != nil {
+		return nil, err
+	}
+	if idx.estBlockUncomp, err = readVarint(); err != nil {
+		return nil, err
+	}
+	alg, err := readVarint()
+	if err != nil {
+		return nil, err
+	}
+	idx.DigestAlg = DigestAlg(alg)
+	n, err := readVarint()
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 || n > maxIndexEntries {
+		return nil, fmt.Errorf("minlz: invalid index entry count %d", n)
+	}
+	for j := int64(0); j < n; j++ {
+		uOff, err := readVarint()
+		if err != nil {
+			return nil, err
+		}
+		cOff, err := readVarint()
+		if err != nil {
+			return nil, err
+		}
+		if cOff < 0 || cOff > size {
+			return nil, fmt.Errorf("minlz: index entry compressed offset %d exceeds stream size %d", cOff, size)
+		}
+		if err := idx.add(uOff, cOff); err != nil {
+			return nil, err
+		}
+	}
+	return idx, nil
+}
+
+// IndexStream builds an Index for a stream written without one, by walking
+// every chunk header from offset 0 to size. It only needs to parse the
+// cheap per-block length prefix (via DecodedLen), not decode any block.
+func IndexStream(r io.ReaderAt, size int64) (*Index, error) {
+	idx := &Index{}
+	idx.reset(0)
+	var pos int64
+	var uPos int64
+	var hdr [4]byte
+
+	for pos < size {
+		if _, err := r.ReadAt(hdr[:], pos); err != nil {
+			return nil, fmt.Errorf("minlz: reading chunk header at %d: %w", pos, err)
+		}
+		typ := hdr[0]
+		chunkLen := int64(hdr[1]) | int64(hdr[2])<<8 | int64(hdr[3])<<16
+		dataStart := pos + 4
+		if dataStart+chunkLen > size {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		switch typ {
+		case chunkDigestAlg:
+			if chunkLen != 1 {
+				return nil, ErrCorrupt
+			}
+			var alg [1]byte
+			if _, err := r.ReadAt(alg[:], dataStart); err != nil {
+				return nil, err
+			}
+			idx.DigestAlg = DigestAlg(alg[0])
+		case chunkMinLZBlock, chunkMinLZCompCRC:
+			// chunkMinLZBlock carries no checksum prefix.
+			crcLen := int64(4)
+			if typ == chunkMinLZBlock {
+				crcLen = 0
+			}
+			n := chunkLen - crcLen - int64(idx.DigestAlg.Size())
+			if n < 0 {
+				return nil, ErrCorrupt
+			}
+			block := make([]byte, n)
+			if _, err := r.ReadAt(block, dataStart+crcLen); err != nil {
+				return nil, err
+			}
+			dLen, err := DecodedLen(block)
+			if err != nil {
+				return nil, err
+			}
+			if err := idx.add(uPos, pos); err != nil {
+				return nil, err
+			}
+			uPos += int64(dLen)
+		case chunkUncompressed:
+			n := chunkLen - 4
+			if err := idx.add(uPos, pos); err != nil {
+				return nil, err
+			}
+			uPos += n
+		}
+		pos = dataStart + chunkLen
+	}
+	idx.TotalUncompressed = uPos
+	idx.TotalCompressed = pos
+	return idx, nil
+}
+
+// seekCacheEntries bounds the number of decoded blocks a SeekableReader
+// keeps around, in LRU order, to avoid re-decoding a block on every small
+// ReadAt into it.
+const seekCacheEntries = 4
+
+// blockCache is a small fixed-capacity LRU of decoded blocks, keyed by the
+// compressed offset they were decoded from.
+type blockCache struct {
+	mu      sync.Mutex
+	entries []cachedBlock
+}
+
+type cachedBlock struct {
+	offset int64
+	data   []byte
+}
+
+func (c *blockCache) get(offset int64) []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, e := range c.entries {
+		if e.offset == offset {
+			copy(c.entries[1:i+1], c.entries[:i])
+			c.entries[0] = e
+			return e.data
+		}
+	}
+	return nil
+}
+
+func (c *blockCache) put(offset int64, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = append([]cachedBlock{{offset, data}}, c.entries...)
+	if len(c.entries) > seekCacheEntries {
+		c.entries = c.entries[:seekCacheEntries]
+	}
+}
+
+// SeekableReader provides io.ReadSeeker and io.ReaderAt access to the
+// uncompressed content of a MinLZ stream backed by an io.ReaderAt, decoding
+// only the block(s) needed to satisfy each request. Recently decoded
+// blocks are kept in a small LRU cache so repeated or overlapping reads
+// into the same block don't re-decode it.
+type SeekableReader struct {
+	ra    io.ReaderAt
+	idx   *Index
+	pos   int64
+	cache blockCache
+}
+
+// NewSeekableReaderAt wraps r using idx, which must describe the stream
+// stored in r (e.g. built by IndexStream, loaded with LoadIndex, or
+// produced by a matching SeekableWriter).
+func NewSeekableReaderAt(r io.ReaderAt, size int64, idx *Index) (*SeekableReader, error) {
+	if idx == nil {
+		return nil, fmt.Errorf("minlz: NewSeekableReaderAt requires a non-nil Index")
+	}
+	return &SeekableReader{ra: r, idx: idx}, nil
+}
+
+// NewSeekableReaderScan builds an Index by scanning the stream (see
+// IndexStream) and returns a SeekableReader over it.
+func NewSeekableReaderScan(ra io.ReaderAt, size int64) (*SeekableReader, error) {
+	idx, err := IndexStream(ra, size)
+	if err != nil {
+		return nil, err
+	}
+	return NewSeekableReaderAt(ra, size, idx)
+}
+
+// OpenSeekableReader is the common entry point for reading a stream written
+// by SeekableWriter or WriterSeekable(true): it loads the trailing index
+// footer with LoadIndex and wraps ra with it, without requiring the caller
+// to handle the Index separately. Streams without a footer (e.g. ones
+// written by a plain Writer) should use NewSeekableReaderScan instead.
+func OpenSeekableReader(ra io.ReaderAt, size int64) (*SeekableReader, error) {
+	idx, err := LoadIndex(ra, size)
+	if err != nil {
+		return nil, err
+	}
+	return NewSeekableReaderAt(ra, size, idx)
+}
+
+// readSeekerAt adapts an io.ReadSeeker to io.ReaderAt by serializing
+// Seek+Read pairs under a mutex. SeekableReader never issues concurrent
+// ReadAt calls against a single reader itself, but the lock keeps a caller
+// doing so from corrupting the underlying seek position.
+type readSeekerAt struct {
+	mu sync.Mutex
+	rs io.ReadSeeker
+}
+
+func (r *readSeekerAt) ReadAt(p []byte, off int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.rs.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return io.ReadFull(r.rs, p)
+}
+
+// NewSeekableReader wraps r, an io.ReadSeeker over a complete MinLZ stream,
+// as a random-access SeekableReader. It first tries to load a trailing
+// index footer written by WriterSeekable(true) or SeekableWriter (see
+// LoadIndex); if none is present, it falls back to scanning every chunk
+// header in the stream (see IndexStream).
+func NewSeekableReader(r io.ReadSeeker) (*SeekableReader, error) {
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	ra := &readSeekerAt{rs: r}
+	if idx, err := LoadIndex(ra, size); err == nil {
+		return NewSeekableReaderAt(ra, size, idx)
+	}
+	return NewSeekableReaderScan(ra, size)
+}
+
+// Size returns the total uncompressed size of the stream.
+func (s *SeekableReader) Size() int64 { return s.idx.TotalUncompressed }
+
+// ReadAt implements io.ReaderAt over the uncompressed content.
+func (s *SeekableReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("minlz: negative offset %d", off)
+	}
+	if off >= s.idx.TotalUncompressed {
+		return 0, io.EOF
+	}
+	n := 0
+	for n < len(p) {
+		cur := off + int64(n)
+		if cur >= s.idx.TotalUncompressed {
+			return n, io.EOF
+		}
+		compOff, uOff, err := s.idx.Find(cur)
+		if err != nil {
+			return n, err
+		}
+		block, err := s.decodeBlockAt(compOff)
+		if err != nil {
+			return n, err
+		}
+		inBlock := int(cur - uOff)
+		if inBlock >= len(block) {
+			return n, ErrCorrupt
+		}
+		copied := copy(p[n:], block[inBlock:])
+		n += copied
+	}
+	return n, nil
+}
+
+// decodeBlockAt reads and decodes the framed block chunk starting at
+// compOff, returning its decoded bytes.
+func (s *SeekableReader) decodeBlockAt(compOff int64) (block []byte, err error) {
+	if cached := s.cache.get(compOff); cached != nil {
+		return cached, nil
+	}
+	var hdr [4]byte
+	if _, err := s.ra.ReadAt(hdr[:], compOff); err != nil {
+		return nil, err
+	}
+	typ := hdr[0]
+	n := int64(hdr[1]) | int64(hdr[2])<<8 | int64(hdr[3])<<16
+	data := make([]byte, n)
+	if _, err := s.ra.ReadAt(data, compOff+4); err != nil {
+		return nil, err
+	}
+	var wantDigest []byte
+	if n := s.idx.DigestAlg.Size(); n > 0 {
+		if len(data) < n {
+			return nil, ErrCorrupt
+		}
+		wantDigest = data[len(data)-n:]
+		data = data[:len(data)-n]
+	}
+	// chunkMinLZBlock carries no checksum prefix; the other types do.
+	hasCRC := typ != chunkMinLZBlock
+	var want uint32
+	if hasCRC {
+		if len(data) < 4 {
+			return nil, ErrCorrupt
+		}
+		want = binary.LittleEndian.Uint32(data)
+		data = data[4:]
+	}
+	switch typ {
+	case chunkMinLZBlock, chunkMinLZCompCRC:
+		block, err = Decode(nil, data)
+	case chunkUncompressed:
+		block = data
+	default:
+		return nil, fmt.Errorf("minlz: unexpected chunk type %#x in index", typ)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if hasCRC && checksum(block) != want {
+		return nil, ErrCorrupt
+	}
+	if s.idx.DigestAlg != DigestNone {
+		if block, err = decodeGoVerify(block, wantDigest, s.idx.DigestAlg); err != nil {
+			return nil, err
+		}
+	}
+	s.cache.put(compOff, block)
+	return block, nil
+}
+
+// Read implements io.Reader, advancing the reader's internal position.
+func (s *SeekableReader) Read(p []byte) (int, error) {
+	n, err := s.ReadAt(p, s.pos)
+	s.pos += int64(n)
+	return n, err
+}
+
+// Seek implements io.Seeker.
+func (s *SeekableReader) Seek(offset int64, whence int) (int64, error) {
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = s.pos + offset
+	case io.SeekEnd:
+		abs = s.idx.TotalUncompressed + offset
+	default:
+		return 0, fmt.Errorf("minlz: invalid whence %d", whence)
+	}
+	if abs < 0 {
+		return 0, fmt.Errorf("minlz: negative position")
+	}
+	s.pos = abs
+	return abs, nil
+}
+
+// SeekableWriter wraps a Writer, recording the (uncompressed, compressed)
+// offset of every flushed block so Close can append an index trailer that
+// LoadIndex recovers without re-scanning the stream.
+type SeekableWriter struct {
+	w    *Writer
+	cw   *countingWriter
+	idx  Index
+	seen int64 // uncompressed bytes observed via Write so far
+}
+
+// countingWriter tracks how many bytes have been written to the
+// underlying io.Writer, so SeekableWriter can record compressed offsets.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// NewSeekableWriter returns a SeekableWriter that writes a framed, indexed
+// MinLZ stream to w.
+func NewSeekableWriter(w io.Writer, opts ...WriterOption) *SeekableWriter {
+	cw := &countingWriter{w: w}
+	sw := &SeekableWriter{w: NewWriter(cw, opts...), cw: cw}
+	sw.w.seekable = true
+	sw.idx.reset(int64(sw.w.blockSize))
+	sw.idx.TotalCompressed = -1
+	sw.idx.TotalUncompressed = -1
+	return sw
+}
+
+// Write implements io.Writer. The wrapped Writer runs in seekable mode, so
+// it records an index entry for every block as that block is written out;
+// calls with empty input write no block and so add no entry.
+func (sw *SeekableWriter) Write(p []byte) (int, error) {
+	n, err := sw.w.Write(p)
+	sw.seen += int64(n)
+	return n, err
+}
+
+// Flush flushes any buffered data without closing the stream.
+func (sw *SeekableWriter) Flush() error { return sw.w.Flush() }
+
+// Close flushes any buffered data and appends the index trailer.
+func (sw *SeekableWriter) Close() error {
+	// The wrapped Writer's Close appends the index it recorded.
+	return sw.w.Close()
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriterSeekableRandomAccess(t *testing.T) {
+	data := []byte(strings.Repeat("minlz seekable stream test data ", 2000))
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, WriterSeekable(true), WriterBlockSize(4096))
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	sr, err := NewSeekableReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("NewSeekableReader: %v", err)
+	}
+	if sr.Size() != int64(len(data)) {
+		t.Fatalf("Size() = %d, want %d", sr.Size(), len(data))
+	}
+
+	for _, off := range []int64{0, 1, 4095, 4096, 10000, int64(len(data)) - 5} {
+		want := data[off : off+5]
+		got := make([]byte, 5)
+		if _, err := sr.ReadAt(got, off); err != nil {
+			t.Fatalf("ReadAt(%d): %v", off, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("ReadAt(%d) = %q, want %q", off, got, want)
+		}
+	}
+
+	// Re-reading the same range should hit the block cache and still match.
+	got := make([]byte, 5)
+	if _, err := sr.ReadAt(got, 0); err != nil {
+		t.Fatalf("cached ReadAt(0): %v", err)
+	}
+	if !bytes.Equal(got, data[:5]) {
+		t.Fatalf("cached ReadAt(0) = %q, want %q", got, data[:5])
+	}
+}
+
+func TestLoadIndexRejectsOffsetBeyondStream(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, WriterSeekable(true))
+	if _, err := w.Write([]byte("some data")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// Truncating the stream by one byte moves the trailer magic off the end,
+	// so LoadIndex must report an error rather than return an Index.
+	raw := buf.Bytes()
+	truncated := raw[:len(raw)-1]
+	if _, err := LoadIndex(bytes.NewReader(truncated), int64(len(truncated))); err == nil {
+		t.Fatal("want error loading index from truncated stream, got nil")
+	}
+
+	// An index whose entry points past the end of the stream it is loaded
+	// from must be rejected rather than handed to a SeekableReader.
+	var idx Index
+	idx.reset(0)
+	if err := idx.add(0, 1<<20); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	footer := idx.appendTo(nil)
+	if _, err := LoadIndex(bytes.NewReader(footer), int64(len(footer))); err == nil {
+		t.Fatal("want error loading index with an entry past the stream end, got nil")
+	}
+}
@@ -0,0 +1,437 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Chunk types and framing constants for the Snappy stream format, as used by
+// the reference C++ implementation. They intentionally share layout (but not
+// numeric chunk-type values in every case) with the MinLZ framing in
+// stream.go: 1-byte type + 3-byte little-endian length.
+const (
+	snappyChunkCompressed   = 0x00
+	snappyChunkUncompressed = 0x01
+	snappyChunkPadding      = 0xfe
+	snappyChunkStreamID     = 0xff
+
+	snappyMagicBody = "sNaPpY"
+
+	// snappyMaxBlockSize is the largest uncompressed chunk the Snappy framing
+	// format allows.
+	snappyMaxBlockSize = 65536
+)
+
+// NewSnappyWriter returns a Writer-like io.WriteCloser that frames its output
+// as a Snappy stream (the "\xff\x06\x00\x00sNaPpY" stream identifier,
+// followed by 0x00/0x01 chunks with masked CRC32C checksums) instead of the
+// MinLZ framing NewWriter produces. This lets callers produce output that
+// any stock Snappy decoder can read.
+func NewSnappyWriter(w io.Writer) *SnappyWriter {
+	return &SnappyWriter{w: w}
+}
+
+// SnappyWriter writes the Snappy stream format, compressing each block with
+// MinLZ's Snappy-compatible block encoder.
+type SnappyWriter struct {
+	w          io.Writer
+	buf        []byte
+	wroteMagic bool
+	err        error
+}
+
+func (w *SnappyWriter) writeStreamID() error {
+	if w.wroteMagic {
+		return nil
+	}
+	w.wroteMagic = true
+	_, err := w.w.Write(frameChunk(snappyChunkStreamID, []byte(snappyMagicBody)))
+	return err
+}
+
+// Write implements io.Writer, splitting p into blocks no larger than the
+// Snappy format's 65536-byte limit.
+func (w *SnappyWriter) Write(p []byte) (n int, err error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	if err := w.writeStreamID(); err != nil {
+		w.err = err
+		return 0, err
+	}
+	n = len(p)
+	for len(p) > 0 {
+		take := snappyMaxBlockSize - len(w.buf)
+		if take > len(p) {
+			take = len(p)
+		}
+		w.buf = append(w.buf, p[:take]...)
+		p = p[take:]
+		if len(w.buf) >= snappyMaxBlockSize {
+			if err := w.flushBlock(); err != nil {
+				w.err = err
+				return n - len(p), err
+			}
+		}
+	}
+	return n, nil
+}
+
+func (w *SnappyWriter) flushBlock() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	block := w.buf
+	w.buf = nil
+
+	compressed := encodeSnappyBlock(nil, block)
+	if len(compressed) < len(block) {
+		// Checksum, then the block's uvarint decoded length, then the tags.
+		payload := binary.LittleEndian.AppendUint32(nil, checksum(block))
+		payload = binary.AppendUvarint(payload, uint64(len(block)))
+		payload = append(payload, compressed...)
+		_, err := w.w.Write(frameChunk(snappyChunkCompressed, payload))
+		return err
+	}
+	payload := make([]byte, 4+len(block))
+	binary.LittleEndian.PutUint32(payload, checksum(block))
+	copy(payload[4:], block)
+	_, err := w.w.Write(frameChunk(snappyChunkUncompressed, payload))
+	return err
+}
+
+// Flush writes any buffered data as a chunk without closing the stream.
+func (w *SnappyWriter) Flush() error {
+	if w.err != nil {
+		return w.err
+	}
+	if err := w.writeStreamID(); err != nil {
+		w.err = err
+		return err
+	}
+	return w.flushBlock()
+}
+
+// Close flushes any buffered data. The Snappy framing format has no
+// explicit end-of-stream marker; callers signal EOF by closing w.
+func (w *SnappyWriter) Close() error {
+	return w.Flush()
+}
+
+// NewSnappyReader returns a reader that decodes a Snappy stream, as produced
+// by NewSnappyWriter or any other conforming Snappy encoder.
+func NewSnappyReader(r io.Reader) *SnappyReader {
+	return &SnappyReader{r: r}
+}
+
+// SnappyReader reads the Snappy stream format.
+type SnappyReader struct {
+	r          io.Reader
+	readHeader bool
+	pending    []byte
+	err        error
+}
+
+func (r *SnappyReader) readChunk() (typ byte, data []byte, err error) {
+	var hdr [4]byte
+	if _, err := io.ReadFull(r.r, hdr[:1]); err != nil {
+		return 0, nil, err
+	}
+	if _, err := io.ReadFull(r.r, hdr[1:4]); err != nil {
+		return 0, nil, io.ErrUnexpectedEOF
+	}
+	n := int(hdr[1]) | int(hdr[2])<<8 | int(hdr[3])<<16
+	data = make([]byte, n)
+	if _, err := io.ReadFull(r.r, data); err != nil {
+		return 0, nil, io.ErrUnexpectedEOF
+	}
+	return hdr[0], data, nil
+}
+
+// Read implements io.Reader.
+func (r *SnappyReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	for len(r.pending) == 0 {
+		typ, data, err := r.readChunk()
+		if err != nil {
+			if err == io.EOF {
+				r.err = io.EOF
+			} else {
+				r.err = err
+			}
+			return 0, r.err
+		}
+		switch {
+		case typ == snappyChunkStreamID:
+			if string(data) != snappyMagicBody {
+				r.err = ErrCorrupt
+				return 0, r.err
+			}
+			r.readHeader = true
+		case typ == snappyChunkCompressed:
+			if !r.readHeader || len(data) < 4 {
+				r.err = ErrCorrupt
+				return 0, r.err
+			}
+			want := binary.LittleEndian.Uint32(data)
+			dLen, l := binary.Uvarint(data[4:])
+			if l <= 0 || dLen > snappyMaxBlockSize {
+				r.err = ErrCorrupt
+				return 0, r.err
+			}
+			block, err := decodeSnappyBlock(nil, data[4+l:])
+			if err != nil {
+				r.err = err
+				return 0, r.err
+			}
+			if uint64(len(block)) != dLen || checksum(block) != want {
+				r.err = ErrCorrupt
+				return 0, r.err
+			}
+			r.pending = block
+		case typ == snappyChunkUncompressed:
+			if !r.readHeader || len(data) < 4 {
+				r.err = ErrCorrupt
+				return 0, r.err
+			}
+			want := binary.LittleEndian.Uint32(data)
+			block := data[4:]
+			if checksum(block) != want {
+				r.err = ErrCorrupt
+				return 0, r.err
+			}
+			r.pending = block
+		case typ == snappyChunkPadding:
+			// Ignore.
+		case typ <= maxNonSkippableChunk:
+			r.err = fmt.Errorf("minlz: unknown non-skippable snappy chunk %#x", typ)
+			return 0, r.err
+		default:
+			// Skippable chunk; ignore.
+		}
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// encodeSnappyBlock encodes src as a single raw Snappy block (the decoded
+// length is NOT included; callers that need the Snappy block-API framing
+// must prepend it themselves). It always returns an encoding, even one
+// larger than src; callers compare lengths to detect incompressible input.
+func encodeSnappyBlock(dst, src []byte) []byte {
+	const (
+		tableBits    = 14
+		maxTableSize = 1 << tableBits
+		inputMargin  = 4
+	)
+	if len(src) < 5 {
+		return emitLiteralSnappy(dst, src)
+	}
+
+	var table [maxTableSize]uint32
+	sLimit := len(src) - inputMargin
+	nextEmit := 0
+	s := 0
+
+	for {
+		candidate := 0
+		for {
+			if s > sLimit {
+				goto emitRemainder
+			}
+			cv := binary.LittleEndian.Uint32(src[s:])
+			hash := hash4(cv, tableBits)
+			candidate = int(table[hash])
+			table[hash] = uint32(s)
+			if candidate != s && cv == binary.LittleEndian.Uint32(src[candidate:]) {
+				break
+			}
+			s++
+		}
+
+		if nextEmit != s {
+			dst = emitLiteralSnappy(dst, src[nextEmit:s])
+		}
+
+		base := s
+		offset := s - candidate
+		candidate += 4
+		s += 4
+		for s < len(src) && src[s] == src[candidate] {
+			candidate++
+			s++
+		}
+		dst = emitCopySnappy(dst, offset, s-base)
+		nextEmit = s
+
+		if s > sLimit {
+			goto emitRemainder
+		}
+		base++
+		for base < s {
+			table[hash4(binary.LittleEndian.Uint32(src[base:]), tableBits)] = uint32(base)
+			base++
+		}
+	}
+
+emitRemainder:
+	if nextEmit < len(src) {
+		dst = emitLiteralSnappy(dst, src[nextEmit:])
+	}
+	return dst
+}
+
+// emitLiteralSnappy appends a Snappy literal tag and lit itself to dst.
+func emitLiteralSnappy(dst, lit []byte) []byte {
+	if len(lit) == 0 {
+		return dst
+	}
+	n := len(lit) - 1
+	switch {
+	case n < 60:
+		dst = append(dst, byte(n)<<2)
+	case n < 1<<8:
+		dst = append(dst, 60<<2, byte(n))
+	case n < 1<<16:
+		dst = append(dst, 61<<2, byte(n), byte(n>>8))
+	case n < 1<<24:
+		dst = append(dst, 62<<2, byte(n), byte(n>>8), byte(n>>16))
+	default:
+		dst = append(dst, 63<<2, byte(n), byte(n>>8), byte(n>>16), byte(n>>24))
+	}
+	return append(dst, lit...)
+}
+
+// emitCopySnappy appends one or more Snappy copy tags encoding a match of
+// the given offset and length, splitting it as needed since a single
+// Snappy copy tag can carry at most 64 bytes.
+func emitCopySnappy(dst []byte, offset, length int) []byte {
+	// Snappy requires the 1-byte-offset "copy with 4-bit offset/length"
+	// variant to have length in [4, 11], so use it only for short matches
+	// with a small offset, and use 2-byte-offset copies otherwise.
+	for length >= 68 {
+		dst = append(dst, byte(63<<2|2), byte(offset), byte(offset>>8))
+		length -= 64
+	}
+	if length > 64 {
+		half := length / 2
+		dst = append(dst, byte((half-1)<<2|2), byte(offset), byte(offset>>8))
+		length -= half
+	}
+	if offset < 2048 && length >= 4 && length <= 11 {
+		dst = append(dst, byte(length-4)<<2|byte(offset>>8)<<5|1, byte(offset))
+		return dst
+	}
+	return append(dst, byte((length-1)<<2|2), byte(offset), byte(offset>>8))
+}
+
+// decodeSnappyBlock decodes a raw Snappy block into dst.
+func decodeSnappyBlock(dst, src []byte) ([]byte, error) {
+	for len(src) > 0 {
+		tag := src[0] & 3
+		value := int(src[0] >> 2)
+		src = src[1:]
+
+		switch tag {
+		case 0: // literal
+			length := value + 1
+			switch value {
+			case 60:
+				if len(src) < 1 {
+					return nil, ErrCorrupt
+				}
+				length = int(src[0]) + 1
+				src = src[1:]
+			case 61:
+				if len(src) < 2 {
+					return nil, ErrCorrupt
+				}
+				length = int(binary.LittleEndian.Uint16(src)) + 1
+				src = src[2:]
+			case 62:
+				if len(src) < 3 {
+					return nil, ErrCorrupt
+				}
+				length = int(src[0]) | int(src[1])<<8 | int(src[2])<<16
+				length++
+				src = src[3:]
+			case 63:
+				if len(src) < 4 {
+					return nil, ErrCorrupt
+				}
+				length = int(binary.LittleEndian.Uint32(src)) + 1
+				src = src[4:]
+			}
+			if length > len(src) {
+				return nil, ErrCorrupt
+			}
+			dst = append(dst, src[:length]...)
+			src = src[length:]
+
+		case 1: // copy with 1-byte offset
+			if len(src) < 1 {
+				return nil, ErrCorrupt
+			}
+			length := value&7 + 4
+			offset := (value>>3)<<8 | int(src[0])
+			src = src[1:]
+			if offset == 0 || offset > len(dst) {
+				return nil, ErrCorrupt
+			}
+			dst = appendCopy(dst, offset, length)
+
+		case 2: // copy with 2-byte offset
+			if len(src) < 2 {
+				return nil, ErrCorrupt
+			}
+			length := value + 1
+			offset := int(binary.LittleEndian.Uint16(src))
+			src = src[2:]
+			if offset == 0 || offset > len(dst) {
+				return nil, ErrCorrupt
+			}
+			dst = appendCopy(dst, offset, length)
+
+		case 3: // copy with 4-byte offset
+			if len(src) < 4 {
+				return nil, ErrCorrupt
+			}
+			length := value + 1
+			offset := int(binary.LittleEndian.Uint32(src))
+			src = src[4:]
+			if offset == 0 || offset > len(dst) {
+				return nil, ErrCorrupt
+			}
+			dst = appendCopy(dst, offset, length)
+		}
+	}
+	return dst, nil
+}
+
+// appendCopy appends length bytes copied from offset bytes before the
+// current end of dst, supporting overlapping (run-length-style) copies.
+func appendCopy(dst []byte, offset, length int) []byte {
+	start := len(dst) - offset
+	for i := 0; i < length; i++ {
+		dst = append(dst, dst[start+i])
+	}
+	return dst
+}
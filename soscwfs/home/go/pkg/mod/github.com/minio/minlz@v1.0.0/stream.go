@@ -0,0 +1,928 @@
+// Copyright 2025 MinIO Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minlz
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// Chunk types for the framed stream format. See internal/reference.ReadStream
+// for the canonical description of the ranges below.
+const (
+	chunkLegacyCompressed = 0x00
+	chunkUncompressed     = 0x01
+	chunkMinLZBlock       = 0x02
+	chunkMinLZCompCRC     = 0x03
+	chunkIndex            = 0x40
+	chunkDictID           = 0x41
+	chunkDigestAlg        = 0x42
+	chunkEOF              = 0x20
+	chunkPadding          = 0xfe
+	chunkStreamID         = 0xff
+
+	maxNonSkippableChunk     = 0x3f
+	minUserSkippableChunk    = 0x80
+	maxUserSkippableChunk    = 0xbf
+	minUserNonSkippableChunk = 0xc0
+	maxUserNonSkippableChunk = 0xfd
+
+	streamMagicBody = "MinLz"
+
+	// defaultBlockSize is the uncompressed size of each framed chunk.
+	defaultBlockSize = 1 << 20
+)
+
+// Exported bounds of the user-chunk id ranges accepted by WriteUserChunk /
+// AddUserChunk, for callers that want to pick an id programmatically (e.g.
+// to lay out several sidecar chunk kinds without hard-coding their values).
+const (
+	MinUserSkippableChunk    = minUserSkippableChunk
+	MaxUserSkippableChunk    = maxUserSkippableChunk
+	MinUserNonSkippableChunk = minUserNonSkippableChunk
+	MaxUserNonSkippableChunk = maxUserNonSkippableChunk
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// maskChecksum applies the same avalanche mask snappy/s2/minlz streams use so
+// that checksums of data that happens to be zero don't collide trivially.
+func maskChecksum(c uint32) uint32 {
+	return c>>15 | c<<17 + 0xa282ead8
+}
+
+func checksum(b []byte) uint32 {
+	return maskChecksum(crc32.Update(0, crcTable, b))
+}
+
+// Writer implements a framed MinLZ stream writer on top of io.Writer.
+type Writer struct {
+	w     io.Writer
+	level Level
+	dict  *Dict
+
+	buf        []byte
+	blockSize  int
+	wroteMagic bool
+	err        error
+	written    int64
+	padding    int
+	crc        bool
+	lowMem     bool
+
+	seekable bool
+	idx      *Index
+	uSeen    int64 // cumulative uncompressed bytes across flushed blocks, when seekable
+
+	digestAlg DigestAlg
+
+	concurrency int
+	pool        *parallelPool
+	seq         uint64
+
+	cdc *cdcChunker
+}
+
+// WriterOption configures a Writer created with NewWriter.
+type WriterOption func(*Writer) error
+
+// WriterLevel sets the compression level used for every emitted block.
+func WriterLevel(level Level) WriterOption {
+	return func(w *Writer) error {
+		if level < LevelFastest || level > LevelSmallest {
+			return ErrInvalidLevel
+		}
+		w.level = level
+		return nil
+	}
+}
+
+// WithDictionary attaches a shared dictionary to the writer. A dictionary-id
+// chunk is emitted once at the start of the stream so that a matching
+// ReaderDict can verify the reader has the same dictionary bytes before
+// trusting any offsets that reach into it.
+func WithDictionary(dict []byte) WriterOption {
+	return func(w *Writer) error {
+		w.dict = NewDict(dict)
+		return nil
+	}
+}
+
+// WithDict is like WithDictionary, but takes an already-built Dict (e.g. one
+// returned by TrainDict) instead of building one from raw bytes.
+func WithDict(d *Dict) WriterOption {
+	return func(w *Writer) error {
+		w.dict = d
+		return nil
+	}
+}
+
+// WriterBlockSize sets the uncompressed size of each emitted chunk.
+func WriterBlockSize(n int) WriterOption {
+	return func(w *Writer) error {
+		if n <= 0 || n > MaxBlockSize {
+			return fmt.Errorf("minlz: invalid block size %d", n)
+		}
+		w.blockSize = n
+		return nil
+	}
+}
+
+// WriterCRC controls whether each emitted block chunk carries a checksum.
+// Disabling it (WriterCRC(false)) saves a CRC32C pass per block, worthwhile
+// when an outer transport (TLS, QUIC) already guarantees integrity. Readers
+// reject unchecked chunks by default; pair this with ReaderVerifyCRC(false)
+// on the reader side.
+func WriterCRC(enabled bool) WriterOption {
+	return func(w *Writer) error {
+		w.crc = enabled
+		return nil
+	}
+}
+
+// lowMemTableBits is the match-finder hash-table size used in WriterLowMem
+// mode, versus the default defaultTableBits.
+const lowMemTableBits = 14
+
+// WriterLowMem trades compression ratio for a smaller per-Writer memory
+// footprint, modeled on zstd's WithLowerEncoderMem. Enabling it shrinks the
+// match-finder hash table from 1<<defaultTableBits to 1<<lowMemTableBits
+// entries (256KiB down to 64KiB of table per concurrent block, at 4 bytes an
+// entry), at the cost of finding fewer long-range matches. It has no effect
+// on the size of w.buf or, when WithConcurrency is set, the number of blocks
+// in flight at once; those are still governed by WriterBlockSize and
+// WithConcurrency.
+func WriterLowMem(enabled bool) WriterOption {
+	return func(w *Writer) error {
+		w.lowMem = enabled
+		return nil
+	}
+}
+
+// WriterSeekable enables writing a trailing index chunk (see Index) on
+// Close, recording the (uncompressed, compressed) offset of every emitted
+// block so a SeekableReader can later decode an arbitrary range of the
+// stream without starting from the beginning. Pairs with NewSeekableReader,
+// OpenSeekableReader, or LoadIndex on the read side.
+func WriterSeekable(enabled bool) WriterOption {
+	return func(w *Writer) error {
+		w.seekable = enabled
+		return nil
+	}
+}
+
+// WriterAddIndex is an alias for WriterSeekable, named to match cmd/mz's
+// -index flag and the seek-index terminology used elsewhere in this package
+// (see Index, LoadIndex, SeekableWriter).
+func WriterAddIndex(enabled bool) WriterOption {
+	return WriterSeekable(enabled)
+}
+
+// WriterDigest enables a strong per-block digest (in addition to the regular
+// CRC32C checksum), appended after each block's encoded payload. Unlike the
+// CRC, which only guards against accidental corruption, the digest is
+// collision-resistant enough to use for content-addressable deduplication of
+// blocks. alg must be DigestNone (the default, disabling this) or one of the
+// algorithms it supports; encoding fails at Write/Close time if the matching
+// build tag (see digest.go) wasn't compiled in.
+func WriterDigest(alg DigestAlg) WriterOption {
+	return func(w *Writer) error {
+		w.digestAlg = alg
+		return nil
+	}
+}
+
+// recordIndexEntry notes that a block of uncompressedLen uncompressed bytes
+// is about to be written starting at the stream's current write offset.
+func (w *Writer) recordIndexEntry(uncompressedLen int) {
+	if w.idx == nil {
+		w.idx = &Index{}
+		w.idx.reset(int64(w.blockSize))
+	}
+	// Offsets are always strictly increasing when recorded in block write
+	// order, so this can never fail.
+	_ = w.idx.add(w.uSeen, w.written)
+	w.uSeen += int64(uncompressedLen)
+}
+
+// maxPaddingChunkSize is the largest single padding chunk's body size; larger
+// padding requests are split across multiple chunks.
+const maxPaddingChunkSize = 1 << 20
+
+// WriterPadding pads the stream (with one or more chunkPadding chunks,
+// written on Close) so the total number of bytes written to the
+// underlying io.Writer is always a multiple of n, e.g. to obscure the
+// plaintext size of the compressed payload from an observer of the
+// ciphertext when the stream is itself encrypted afterwards. n must be a
+// power of two no larger than maxPaddingChunkSize. With WriterSeekable also
+// set, the index trailer is appended after the padded stream and is not
+// itself padded, so the total is then not a multiple of n.
+func WriterPadding(n int) WriterOption {
+	return func(w *Writer) error {
+		if n <= 0 || n&(n-1) != 0 || n > maxPaddingChunkSize {
+			return fmt.Errorf("minlz: invalid padding %d: must be a power of two up to %d", n, maxPaddingChunkSize)
+		}
+		w.padding = n
+		return nil
+	}
+}
+
+// padTo emits chunkPadding chunks so that, once the eofExtra bytes still to
+// come (the stream terminator) are added, the total written to w.w is a
+// multiple of w.padding.
+func (w *Writer) padTo(eofExtra int64) error {
+	n := int64(w.padding)
+	target := ((w.written + eofExtra + n - 1) / n) * n
+	need := target - w.written - eofExtra
+	for need > 0 && need < 4 {
+		// A padding chunk always costs at least 4 bytes of header; move on to
+		// the next multiple (repeatedly, for n < 4) instead of trying to pad
+		// a partial header.
+		need += n
+	}
+	zeros := make([]byte, maxPaddingChunkSize)
+	for need > 0 {
+		body := need - 4
+		if body > maxPaddingChunkSize {
+			body = maxPaddingChunkSize
+		}
+		if err := w.writeChunk(chunkPadding, zeros[:body]); err != nil {
+			return err
+		}
+		need -= 4 + body
+	}
+	return nil
+}
+
+// NewWriter returns a new Writer that writes a framed MinLZ stream to w.
+func NewWriter(w io.Writer, opts ...WriterOption) *Writer {
+	wr := &Writer{w: w, blockSize: defaultBlockSize, crc: true}
+	for _, o := range opts {
+		if err := o(wr); err != nil {
+			wr.err = err
+		}
+	}
+	return wr
+}
+
+func (w *Writer) writeStreamID() error {
+	if w.wroteMagic {
+		return nil
+	}
+	w.wroteMagic = true
+	body := []byte(streamMagicBody)
+	if err := w.writeChunk(chunkStreamID, body); err != nil {
+		return err
+	}
+	if w.dict != nil {
+		if err := w.writeChunk(chunkDictID, w.dict.chunkPayload()); err != nil {
+			return err
+		}
+	}
+	if w.digestAlg != DigestNone {
+		return w.writeChunk(chunkDigestAlg, []byte{byte(w.digestAlg)})
+	}
+	return nil
+}
+
+// frameChunk returns data wrapped in a chunk header (type byte + 3-byte
+// little-endian length), ready to be written verbatim to the stream.
+func frameChunk(typ byte, data []byte) []byte {
+	out := make([]byte, 4+len(data))
+	out[0] = typ
+	out[1] = byte(len(data))
+	out[2] = byte(len(data) >> 8)
+	out[3] = byte(len(data) >> 16)
+	copy(out[4:], data)
+	return out
+}
+
+// putChecksum writes the masked CRC32C of block into buf[0:4].
+func putChecksum(buf, block []byte) {
+	binary.LittleEndian.PutUint32(buf, checksum(block))
+}
+
+func (w *Writer) writeChunk(typ byte, data []byte) error {
+	if len(data) > 1<<24-1 {
+		return errors.New("minlz: chunk too large")
+	}
+	n, err := w.w.Write(frameChunk(typ, data))
+	w.written += int64(n)
+	return err
+}
+
+// Write implements io.Writer. Input is buffered and split into independent
+// blocks of roughly w.blockSize bytes.
+func (w *Writer) Write(p []byte) (n int, err error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	if err := w.writeStreamID(); err != nil {
+		w.err = err
+		return 0, err
+	}
+	n = len(p)
+	if w.cdc != nil {
+		for _, b := range p {
+			w.buf = append(w.buf, b)
+			if w.cdc.feed(b) {
+				if err := w.flushBlock(); err != nil {
+					w.err = err
+					return n, err
+				}
+			}
+		}
+		return n, nil
+	}
+	for len(p) > 0 {
+		take := w.blockSize - len(w.buf)
+		if take > len(p) {
+			take = len(p)
+		}
+		w.buf = append(w.buf, p[:take]...)
+		p = p[take:]
+		if len(w.buf) >= w.blockSize {
+			if err := w.flushBlock(); err != nil {
+				w.err = err
+				return n - len(p), err
+			}
+		}
+	}
+	return n, nil
+}
+
+func (w *Writer) flushBlock() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	block := w.buf
+	w.buf = nil
+
+	if w.concurrency > 1 {
+		if w.pool == nil {
+			w.pool = newParallelPool(w, w.concurrency)
+		}
+		seq := w.seq
+		w.seq++
+		w.pool.submit(seq, block)
+		return w.pool.drainReady(false)
+	}
+
+	chunk, err := w.encodeFramedBlock(block)
+	if err != nil {
+		return err
+	}
+	if w.seekable {
+		w.recordIndexEntry(len(block))
+	}
+	n, err := w.w.Write(chunk)
+	w.written += int64(n)
+	return err
+}
+
+// Flush writes any buffered data as a block without closing the stream.
+func (w *Writer) Flush() error {
+	if w.err != nil {
+		return w.err
+	}
+	if err := w.writeStreamID(); err != nil {
+		w.err = err
+		return err
+	}
+	return w.flushBlock()
+}
+
+// WriteUserChunk emits a user-defined chunk with the given id and payload.
+// id must fall in the user-skippable (0x80-0xbf) or user-non-skippable
+// (0xc0-0xfd) range, so conforming decoders that don't recognize id either
+// ignore the chunk or reject the stream, per the framing format's rules.
+// This lets applications embed sidecar metadata (filenames, offsets,
+// dictionary IDs, application headers) inside a MinLZ stream.
+func (w *Writer) WriteUserChunk(id byte, payload []byte) error {
+	if w.err != nil {
+		return w.err
+	}
+	if id < minUserSkippableChunk || id > maxUserNonSkippableChunk {
+		return fmt.Errorf("minlz: user chunk id %#x out of range [%#x, %#x]", id, minUserSkippableChunk, maxUserNonSkippableChunk)
+	}
+	if err := w.writeStreamID(); err != nil {
+		w.err = err
+		return err
+	}
+	if err := w.writeChunk(id, payload); err != nil {
+		w.err = err
+		return err
+	}
+	return nil
+}
+
+// AddUserChunk is an alias for WriteUserChunk.
+func (w *Writer) AddUserChunk(id byte, payload []byte) error {
+	return w.WriteUserChunk(id, payload)
+}
+
+// EnableIndex is equivalent to passing WriterSeekable(enabled) to NewWriter,
+// but can be called on an already-constructed Writer (e.g. by code that
+// doesn't build its WriterOption list until after the Writer exists),
+// provided no block has been flushed yet.
+func (w *Writer) EnableIndex(enabled bool) {
+	w.seekable = enabled
+}
+
+// Reset discards w's buffered state and any error, and reconfigures it to
+// write a fresh stream to dst -- the level, block size, and every other
+// option it was constructed with carry over unchanged. This lets a single
+// Writer be reused across many outputs (e.g. compressing a batch of files)
+// without paying NewWriter's allocation cost for each one.
+func (w *Writer) Reset(dst io.Writer) {
+	w.w = dst
+	w.buf = w.buf[:0]
+	w.wroteMagic = false
+	w.err = nil
+	w.written = 0
+	w.idx = nil
+	w.uSeen = 0
+	w.pool = nil
+	w.seq = 0
+	if w.cdc != nil {
+		w.cdc, _ = newCDCChunker(w.cdc.min, w.cdc.avg, w.cdc.max)
+	}
+}
+
+// ReadFrom implements io.ReaderFrom, reading r to completion and writing it
+// through w in w.blockSize chunks, so callers don't have to drive their own
+// io.Copy(w, r) loop.
+func (w *Writer) ReadFrom(r io.Reader) (int64, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	buf := make([]byte, 64<<10)
+	var total int64
+	for {
+		nr, er := r.Read(buf)
+		if nr > 0 {
+			nw, ew := w.Write(buf[:nr])
+			total += int64(nw)
+			if ew != nil {
+				return total, ew
+			}
+			if nw != nr {
+				return total, io.ErrShortWrite
+			}
+		}
+		if er != nil {
+			if er == io.EOF {
+				return total, nil
+			}
+			return total, er
+		}
+	}
+}
+
+// EncodeBuffer writes all of b to the stream in one call. It's equivalent
+// to Write(b) but returns only the error, for callers (e.g. the benchmark
+// harness) that already hold the entire input in memory and don't need
+// Write's byte count.
+func (w *Writer) EncodeBuffer(b []byte) error {
+	_, err := w.Write(b)
+	return err
+}
+
+// Close flushes any buffered data, waits for any in-flight parallel blocks,
+// and writes the stream terminator.
+func (w *Writer) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if w.pool != nil {
+		if err := w.pool.closeAndWait(); err != nil {
+			w.err = err
+			return err
+		}
+		w.pool = nil
+	}
+	if w.padding > 0 {
+		if err := w.padTo(4); err != nil {
+			w.err = err
+			return err
+		}
+	}
+	if err := w.writeChunk(chunkEOF, nil); err != nil {
+		w.err = err
+		return err
+	}
+	if w.seekable {
+		if w.idx == nil {
+			w.idx = &Index{}
+			w.idx.reset(int64(w.blockSize))
+		}
+		w.idx.TotalUncompressed = w.uSeen
+		w.idx.TotalCompressed = w.written
+		w.idx.DigestAlg = w.digestAlg
+		n, err := w.w.Write(w.idx.appendTo(nil))
+		w.written += int64(n)
+		if err != nil {
+			w.err = err
+			return err
+		}
+	}
+	return nil
+}
+
+// Reader implements a framed MinLZ stream reader on top of io.Reader.
+type Reader struct {
+	r    io.Reader
+	dict *Dict
+
+	readHeader bool
+	pending    []byte
+	err        error
+
+	userChunk func(id byte, r io.Reader) error
+
+	concurrency int
+	dpool       *decodePool
+
+	requireCRC   bool
+	digestAlg    DigestAlg
+	verifyDigest bool
+
+	seek *SeekableReader
+
+	fallback   bool
+	sniffed    bool
+	fallbackRC io.ReadCloser
+}
+
+// ReaderOption configures a Reader created with NewReader.
+type ReaderOption func(*Reader) error
+
+// ReaderVerifyCRC controls whether the reader requires every chunk to carry
+// a checksum, matching the writer's default. Pass false to read a stream
+// produced with WriterCRC(false); the reader then trusts unchecked chunks
+// instead of rejecting them, so only disable this when something else
+// (TLS, QUIC, an outer integrity check) already covers the payload.
+func ReaderVerifyCRC(verify bool) ReaderOption {
+	return func(r *Reader) error {
+		r.requireCRC = verify
+		return nil
+	}
+}
+
+// ReaderDict attaches the dictionary a stream was encoded with, so Read can
+// resolve copy offsets that reach into it. The reader refuses to decode any
+// stream that carries a dictionary-id chunk that doesn't match d.
+func ReaderDict(d *Dict) ReaderOption {
+	return func(r *Reader) error {
+		r.dict = d
+		return nil
+	}
+}
+
+// dictBytes returns the dictionary bytes to seed decoding with, or nil if
+// none is attached.
+func (r *Reader) dictBytes() []byte {
+	if r.dict == nil {
+		return nil
+	}
+	return r.dict.bytes
+}
+
+// ReaderConcurrency sets the number of goroutines used to decompress blocks.
+// With n > 1, Read pre-reads frame headers from the stream and dispatches
+// their decoding to a worker pool, while still delivering decoded blocks to
+// the caller strictly in stream order.
+func ReaderConcurrency(n int) ReaderOption {
+	return func(r *Reader) error {
+		if n < 1 {
+			n = 1
+		}
+		r.concurrency = n
+		return nil
+	}
+}
+
+// NewReader returns a new Reader that reads a framed MinLZ stream from r.
+func NewReader(r io.Reader, opts ...ReaderOption) *Reader {
+	rd := &Reader{r: r, requireCRC: true, verifyDigest: true}
+	for _, o := range opts {
+		if err := o(rd); err != nil {
+			rd.err = err
+		}
+	}
+	return rd
+}
+
+// VerifyDigests controls whether per-block strong digests (see WriterDigest)
+// are checked as they're read. It is enabled by default; disabling it skips
+// the extra hash computation, trusting the CRC32C checksum alone, which is
+// useful when a caller only wants StreamInfo's negotiated algorithm without
+// paying for verification (e.g. re-framing blocks without re-hashing them).
+func (r *Reader) VerifyDigests(enabled bool) {
+	r.verifyDigest = enabled
+}
+
+// StreamInfo reports properties of the stream negotiated so far, such as the
+// digest algorithm (if any) recorded in the stream's chunkDigestAlg chunk.
+// Because the Reader has no eager header-priming step, the reported values
+// only reflect what's been read up to the most recent Read call; call it
+// after at least one successful Read (or after Close on the writer side, if
+// inspecting a stream you just wrote) to get an accurate picture.
+type StreamInfo struct {
+	DigestAlg DigestAlg
+}
+
+// StreamInfo returns the stream's negotiated properties as observed so far.
+// See the StreamInfo type's doc comment for the timing caveat.
+func (r *Reader) StreamInfo() StreamInfo {
+	return StreamInfo{DigestAlg: r.digestAlg}
+}
+
+// SetUserChunkHandler installs fn to be called for every user-skippable and
+// user-non-skippable chunk (ids 0x80-0xfd) encountered while reading,
+// letting callers consume sidecar metadata written with WriteUserChunk. If
+// fn is nil, or returns without reading r to completion, the chunk's
+// remaining bytes are simply skipped; returning a non-nil error aborts the
+// Read call that triggered it. Chunks outside the user range are still
+// handled internally and never reach fn.
+func (r *Reader) SetUserChunkHandler(fn func(id byte, r io.Reader) error) {
+	r.userChunk = fn
+}
+
+func (r *Reader) readChunk() (typ byte, data []byte, err error) {
+	var hdr [4]byte
+	if _, err := io.ReadFull(r.r, hdr[:1]); err != nil {
+		return 0, nil, err
+	}
+	if _, err := io.ReadFull(r.r, hdr[1:4]); err != nil {
+		return 0, nil, io.ErrUnexpectedEOF
+	}
+	n := int(hdr[1]) | int(hdr[2])<<8 | int(hdr[3])<<16
+	data = make([]byte, n)
+	if _, err := io.ReadFull(r.r, data); err != nil {
+		return 0, nil, io.ErrUnexpectedEOF
+	}
+	return hdr[0], data, nil
+}
+
+// decodeFrameBlock decodes the payload of a chunkMinLZCompCRC,
+// chunkMinLZBlock or chunkUncompressed chunk. chunkMinLZBlock carries no
+// checksum prefix (it's what WriterCRC(false) emits); decoding it fails
+// unless requireCRC is false, mirroring how a ReaderVerifyCRC(false) reader
+// opts into trusting unchecked chunks. If digestAlg is not DigestNone, the
+// trailing digestAlg.Size() bytes of data are a strong digest (see
+// WriterDigest) rather than part of the compressed payload; it is split off
+// before decoding and, when verifyDigest is set, checked against the decoded
+// block via decodeGoVerify.
+func decodeFrameBlock(typ byte, data []byte, dict []byte, requireCRC bool, digestAlg DigestAlg, verifyDigest bool) ([]byte, error) {
+	var wantDigest []byte
+	if digestAlg != DigestNone {
+		n := digestAlg.Size()
+		if len(data) < n {
+			return nil, ErrCorrupt
+		}
+		wantDigest = data[len(data)-n:]
+		data = data[:len(data)-n]
+	}
+
+	var block []byte
+	var err error
+	if typ == chunkMinLZBlock {
+		if requireCRC {
+			return nil, errors.New("minlz: stream has unchecked chunks; use ReaderVerifyCRC(false) to allow them")
+		}
+		if len(dict) > 0 {
+			block, err = DecodeDict(nil, data, dict)
+		} else {
+			block, err = Decode(nil, data)
+		}
+	} else {
+		if len(data) < 4 {
+			return nil, ErrCorrupt
+		}
+		want := binary.LittleEndian.Uint32(data)
+		switch {
+		case typ == chunkUncompressed:
+			block = data[4:]
+		case len(dict) > 0:
+			block, err = DecodeDict(nil, data[4:], dict)
+		default:
+			block, err = Decode(nil, data[4:])
+		}
+		if err == nil && checksum(block) != want {
+			err = ErrCorrupt
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if digestAlg != DigestNone && verifyDigest {
+		return decodeGoVerify(block, wantDigest, digestAlg)
+	}
+	return block, nil
+}
+
+// decodeGoVerify checks block's strong digest (computed under alg) against
+// wantDigest, returning block unchanged on a match and ErrCorrupt on a
+// mismatch. It's named to pair with the package's other decode*Go helpers
+// even though, unlike them, it verifies an already-decoded block rather than
+// decoding one.
+func decodeGoVerify(block, wantDigest []byte, alg DigestAlg) ([]byte, error) {
+	got, err := computeDigest(alg, block)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(got, wantDigest) {
+		return nil, ErrCorrupt
+	}
+	return block, nil
+}
+
+// checkDictID validates the incoming dictionary-id chunk against the Dict
+// installed via ReaderDict, refusing to continue if either the reader has no
+// dict attached or the attached Dict's bytes don't match what the writer
+// used (so copy offsets that reach into the dictionary can't silently
+// resolve to the wrong bytes).
+func (r *Reader) checkDictID(data []byte) error {
+	if len(data) != 8 {
+		return ErrCorrupt
+	}
+	if r.dict == nil {
+		return errors.New("minlz: stream uses a dictionary, but no ReaderDict was provided")
+	}
+	wantID := binary.LittleEndian.Uint32(data[0:4])
+	wantCRC := binary.LittleEndian.Uint32(data[4:8])
+	if wantID != r.dict.ID() || wantCRC != crc32.Checksum(r.dict.bytes, crcTable) {
+		return errors.New("minlz: ReaderDict does not match the dictionary used to encode this stream")
+	}
+	return nil
+}
+
+// ReadSeeker switches r into random-access mode, backed by a SeekableReader
+// over its underlying source, and returns the io.Seeker to position it with.
+// After calling ReadSeeker, r.Read (and ReadByte) serve decoded bytes from
+// the seeker's current position instead of walking the stream sequentially,
+// so cmd/mz's --tail/--offset flags can jump to an arbitrary uncompressed
+// offset in one step. If the source was written with a trailing index
+// footer (see SeekableWriter, WriterSeekable), that footer's table of
+// contents is used directly; otherwise the stream's chunk headers are
+// scanned once up front (see NewSeekableReader).
+//
+// dict attaches the dictionary the stream was encoded with, exactly as
+// ReaderDict does; pass nil if the stream has none. The reader's underlying
+// source (passed to NewReader or Reset) must implement io.ReadSeeker, or
+// ReadSeeker returns an error.
+func (r *Reader) ReadSeeker(dict *Dict) (io.Seeker, error) {
+	if r.err != nil && r.err != io.EOF {
+		return nil, r.err
+	}
+	rs, ok := r.r.(io.ReadSeeker)
+	if !ok {
+		return nil, errors.New("minlz: ReadSeeker requires the reader's source to implement io.ReadSeeker")
+	}
+	if dict != nil {
+		r.dict = dict
+	}
+	sr, err := NewSeekableReader(rs)
+	if err != nil {
+		return nil, err
+	}
+	r.seek = sr
+	r.pending = nil
+	r.err = nil
+	return sr, nil
+}
+
+// Reset discards r's buffered state and any error, and reconfigures it to
+// read a fresh stream from src -- the dict, concurrency, fallback and every
+// other option it was constructed with carry over unchanged. This lets a
+// single Reader be reused across many inputs (e.g. decompressing a batch of
+// files, or the benchmark harness's repeated passes) without paying
+// NewReader's allocation cost each time. Passing a nil src just drops r's
+// reference to its previous source.
+func (r *Reader) Reset(src io.Reader) {
+	r.r = src
+	r.pending = nil
+	r.err = nil
+	r.digestAlg = DigestNone
+	r.seek = nil
+	r.dpool = nil
+	r.sniffed = false
+	r.fallbackRC = nil
+}
+
+// ReadByte implements io.ByteReader over the decoded stream, for callers
+// (e.g. cmd/mz's tail-to-next-newline handling) that need to scan forward a
+// byte at a time after a Seek.
+func (r *Reader) ReadByte() (byte, error) {
+	var b [1]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+// Read implements io.Reader.
+func (r *Reader) Read(p []byte) (int, error) {
+	if r.seek != nil {
+		return r.seek.Read(p)
+	}
+	if r.err != nil {
+		return 0, r.err
+	}
+	if r.fallback && !r.sniffed {
+		if err := r.initFallback(); err != nil {
+			r.err = err
+			return 0, r.err
+		}
+	}
+	if r.fallbackRC != nil {
+		n, err := r.fallbackRC.Read(p)
+		if err == io.EOF {
+			r.err = io.EOF
+		} else if err != nil {
+			r.err = err
+		}
+		return n, err
+	}
+	for len(r.pending) == 0 {
+		if r.concurrency > 1 {
+			block, err := r.nextParallel()
+			if err != nil {
+				r.err = err
+				return 0, r.err
+			}
+			r.pending = block
+			continue
+		}
+		typ, data, err := r.readChunk()
+		if err != nil {
+			if err == io.EOF {
+				r.err = io.EOF
+			} else {
+				r.err = err
+			}
+			return 0, r.err
+		}
+		switch {
+		case typ == chunkStreamID:
+			if string(data) != streamMagicBody {
+				r.err = ErrCorrupt
+				return 0, r.err
+			}
+		case typ == chunkDictID:
+			if err := r.checkDictID(data); err != nil {
+				r.err = err
+				return 0, r.err
+			}
+		case typ == chunkDigestAlg:
+			if len(data) != 1 {
+				r.err = ErrCorrupt
+				return 0, r.err
+			}
+			r.digestAlg = DigestAlg(data[0])
+		case typ == chunkMinLZCompCRC, typ == chunkMinLZBlock, typ == chunkUncompressed:
+			block, err := decodeFrameBlock(typ, data, r.dictBytes(), r.requireCRC, r.digestAlg, r.verifyDigest)
+			if err != nil {
+				r.err = err
+				return 0, r.err
+			}
+			r.pending = block
+		case typ == chunkEOF:
+			// Multiple streams may be concatenated; keep reading.
+		case typ <= maxNonSkippableChunk:
+			r.err = fmt.Errorf("minlz: unknown non-skippable chunk %#x", typ)
+			return 0, r.err
+		case typ >= minUserSkippableChunk && typ <= maxUserNonSkippableChunk && r.userChunk != nil:
+			if err := r.userChunk(typ, bytes.NewReader(data)); err != nil {
+				r.err = err
+				return 0, r.err
+			}
+		default:
+			// Skippable chunk (internal, user-skippable or user-non-skippable
+			// range) with no handler installed; ignore.
+		}
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}